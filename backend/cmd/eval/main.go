@@ -0,0 +1,183 @@
+// Command eval runs the labeled fixtures in fixtures/ through the job
+// extraction and resume comparison prompts and reports how accurate and
+// stable the results are, optionally against a previous run.
+//
+//	eval -out runs/new.json -baseline runs/old.json
+//	eval -provider openai -extraction-model llama3.1 -comparison-model llama3.1
+//	eval -record -recordings runs/groq.jsonl    # live run, saving responses
+//	eval -provider replay -recordings runs/groq.jsonl
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"job-parser-backend/internal/client"
+	"job-parser-backend/internal/config"
+	"job-parser-backend/internal/eval"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/redact"
+	"job-parser-backend/internal/secrets"
+	"job-parser-backend/internal/service"
+	"job-parser-backend/internal/tokens"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+//go:embed fixtures
+var defaultFixtures embed.FS
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Error loading .env file")
+	}
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	providerName := flag.String("provider", "groq", "LLM provider: groq, openai or replay")
+	recordings := flag.String("recordings", "", "recorded responses file, read by -provider replay and written by -record")
+	record := flag.Bool("record", false, "append every response to the -recordings file")
+	fixturesDir := flag.String("fixtures", "", "fixtures directory, defaults to the built-in fixtures")
+	promptsDir := flag.String("prompts", cfg.LLM.PromptsDir, "prompt override directory")
+	extractionModel := flag.String("extraction-model", model.Mixtral_Saba_24b, "model used for job extraction")
+	comparisonModel := flag.String("comparison-model", model.Gemma2_9B_Instruct, "model used for resume comparison")
+	runs := flag.Int("runs", 3, "times each comparison fixture is scored, to measure stability")
+	out := flag.String("out", "", "file to save this run to as JSON")
+	baselinePath := flag.String("baseline", "", "previous run to compare against")
+	reportPath := flag.String("report", "-", "Markdown report file, - for stdout")
+	flag.Parse()
+
+	llmProvider, err := createProvider(cfg, *providerName, *recordings)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *record {
+		if *recordings == "" {
+			log.Fatal("-record needs a -recordings file")
+		}
+		llmProvider, err = client.NewRecordingProvider(llmProvider, *recordings)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	prompts, err := prompt.NewRegistry(*promptsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	limits, err := tokens.LoadLimits(cfg.LLM.TokenLimitsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	redactKinds, err := redact.ParseKinds(cfg.LLM.PIIRedact)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var fixturesFS fs.FS = os.DirFS(*fixturesDir)
+	if *fixturesDir == "" {
+		fixturesFS, _ = fs.Sub(defaultFixtures, "fixtures")
+	}
+	fixtures, err := eval.LoadFixtures(fixturesFS)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var baseline *eval.Run
+	if *baselinePath != "" {
+		baseline, err = readRun(*baselinePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	llm := service.LLM{
+		Provider: llmProvider,
+		Prompts:  prompts,
+		Tokens:   tokens.NewEstimator(limits),
+		// No store, so evaluations are not audited.
+		Redactor: redact.NewRedactor(redactKinds, nil),
+	}
+	evaluator := eval.NewEvaluator(llm, eval.Options{
+		ExtractionModel: *extractionModel,
+		ComparisonModel: *comparisonModel,
+		Runs:            *runs,
+	})
+
+	log.Printf("Evaluating %d extraction and %d comparison fixtures with %s", len(fixtures.Extraction), len(fixtures.Comparison), llmProvider.Name())
+	run := evaluator.Run(fixtures)
+
+	if *out != "" {
+		if err := writeRun(*out, run); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	report := os.Stdout
+	if *reportPath != "-" {
+		report, err = os.Create(*reportPath)
+		if err != nil {
+			log.Fatal("Failed to create report: ", err)
+		}
+		defer report.Close()
+	}
+	if err := eval.WriteReport(report, run, baseline); err != nil {
+		log.Fatal("Failed to write report: ", err)
+	}
+}
+
+func createProvider(cfg *config.Config, name string, recordings string) (client.LLMProvider, error) {
+	switch name {
+	case "groq", "openai":
+		secretStore, err := secrets.CreateStore(cfg.Secrets)
+		if err != nil {
+			return nil, err
+		}
+		if name == "groq" {
+			return client.CreateGroqClient(&http.Client{}, cfg.Groq, secretStore)
+		}
+		return client.CreateOpenAIClient(&http.Client{}, cfg.OpenAI, secretStore)
+	case "replay":
+		if recordings == "" {
+			return nil, fmt.Errorf("-provider replay needs a -recordings file")
+		}
+		return client.NewReplayProvider(recordings)
+	default:
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+}
+
+func readRun(path string) (*eval.Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading run: %w", err)
+	}
+
+	var run eval.Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("error decoding run %s: %w", path, err)
+	}
+	return &run, nil
+}
+
+func writeRun(path string, run *eval.Run) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding run: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing run: %w", err)
+	}
+	return nil
+}
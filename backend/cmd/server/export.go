@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"job-parser-backend/internal/export"
+	"job-parser-backend/internal/model"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// runExport implements `server export`, which writes the same data as
+// GET /api/job/export to a file so it can be scheduled from cron.
+//
+//	server export -format xlsx -status Applied -out backups/
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", export.FormatCSV, "export format: csv, jsonl or xlsx")
+	status := flags.String("status", "", "only export jobs with this status")
+	out := flags.String("out", "-", "output file or directory, - for stdout")
+	flags.Parse(args)
+
+	_, extension, err := export.ContentType(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := loadConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	services, err := createServices(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	jobService := services.jobService
+
+	if *out == "-" {
+		if err := writeExport(context.Background(), os.Stdout, *format, *status, jobService.ExportJobs); err != nil {
+			log.Fatal("Export failed: ", err)
+		}
+		return
+	}
+
+	path := *out
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, fmt.Sprintf("jobs-%s%s", time.Now().Format("20060102-150405"), extension))
+	}
+
+	// Write next to the destination and rename, so an interrupted backup never
+	// replaces a good file with a partial one.
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		log.Fatal("Failed to create export file: ", err)
+	}
+
+	err = writeExport(context.Background(), file, *format, *status, jobService.ExportJobs)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		log.Fatal("Export failed: ", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		log.Fatal("Failed to move export into place: ", err)
+	}
+
+	log.Println("Exported jobs to", path)
+}
+
+func writeExport(ctx context.Context, w io.Writer, format string, status string, exportJobs func(context.Context, string, func(model.Job) error) error) error {
+	writer, err := export.NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	if err := exportJobs(ctx, status, writer.Write); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"job-parser-backend/internal/auth"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// runKeys implements `server keys`, which manages the API keys accepted by
+// the server. The full key is printed once, on creation.
+//
+//	server keys create -name laptop -scopes read,write,compare
+//	server keys list
+//	server keys revoke <id>
+func runKeys(args []string) {
+	if len(args) == 0 {
+		log.Fatal("Usage: server keys create|list|revoke")
+	}
+	authenticator := createAuthenticator(loadConfig().Auth)
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("keys create", flag.ExitOnError)
+		name := flags.String("name", "", "what the key is for, e.g. the device using it")
+		scopes := flags.String("scopes", auth.ScopeRead, "comma-separated scopes: "+strings.Join(auth.Scopes, ", "))
+		user := flags.String("user", "", "user the key acts for, empty for the server's owner")
+		flags.Parse(args[1:])
+
+		if *name == "" {
+			log.Fatal("A key -name is required")
+		}
+		parsed, err := auth.ParseScopes(*scopes)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		key, secret, err := authenticator.CreateKey(*name, *user, parsed)
+		if err != nil {
+			log.Fatal("Failed to create key: ", err)
+		}
+		fmt.Fprintf(os.Stderr, "Created key %s with scopes %s. Store it now, it is not shown again:\n", key.ID, strings.Join(key.Scopes, ","))
+		fmt.Println(secret)
+
+	case "list":
+		keys, err := authenticator.ListKeys()
+		if err != nil {
+			log.Fatal("Failed to list keys: ", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tUSER\tSCOPES\tCREATED\tREVOKED")
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.User, strings.Join(key.Scopes, ","), key.CreatedAt, key.RevokedAt)
+		}
+		w.Flush()
+
+	case "revoke":
+		if len(args) != 2 {
+			log.Fatal("Usage: server keys revoke <id>")
+		}
+		if err := authenticator.RevokeKey(args[1]); err != nil {
+			log.Fatal("Failed to revoke key: ", err)
+		}
+		fmt.Fprintf(os.Stderr, "Revoked key %s and its tokens\n", args[1])
+
+	default:
+		log.Fatalf("Unknown keys command %q, expected create, list or revoke", args[0])
+	}
+}
@@ -1,30 +1,101 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"job-parser-backend/internal/auth"
 	"job-parser-backend/internal/client"
+	"job-parser-backend/internal/config"
+	"job-parser-backend/internal/fallback"
 	"job-parser-backend/internal/handler"
+	"job-parser-backend/internal/llmcache"
+	"job-parser-backend/internal/notify"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/ratelimit"
+	"job-parser-backend/internal/redact"
+	"job-parser-backend/internal/secrets"
 	"job-parser-backend/internal/service"
+	"job-parser-backend/internal/skills"
+	"job-parser-backend/internal/store"
+	"job-parser-backend/internal/tenant"
+	"job-parser-backend/internal/tokens"
+	"job-parser-backend/internal/usage"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
-func Initalize(r *gin.Engine) {
-	// Initialize clients
-	httpClient := &http.Client{}
-	notionClient, groqClient, err := client.CreateClients(httpClient)
+func Initalize(r *gin.Engine, cfg *config.Config, reloader config.Reloader) {
+	// Initialize services
+	services, err := createServices(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
+	// Initialize middleware
+	r.Use(handler.LLMCacheBypass())
+	llmLimit, readLimit, ipLimit, err := rateLimits(cfg.RateLimit)
 	if err != nil {
-		log.Fatal("Failed to create clients: ", err)
+		log.Fatal(err)
 	}
-	// Initialize services
-	jobService := service.NewJobService(notionClient, groqClient)
+	llmLimiter, readLimiter, ipLimiter := ratelimit.NewLimiter(llmLimit), ratelimit.NewLimiter(readLimit), ratelimit.NewLimiter(ipLimit)
+	r.Use(handler.IPRateLimit(ipLimiter))
+	if cfg.Auth.Disabled {
+		log.Println("Authentication is disabled, anyone who can reach the server can use the API")
+	} else {
+		authenticator := createAuthenticator(cfg.Auth)
+		r.Use(handler.Authenticate(authenticator))
+		handler.CreateAuthHandler(authenticator, r)
+	}
+	r.Use(handler.RateLimit(llmLimiter, readLimiter))
+	r.Use(handler.Credentials(services.resolver))
 
 	// Initialize handlers
-	handler.CreateJobHandler(jobService, r)
+	if services.llmCache != nil {
+		handler.CreateLLMCacheHandler(services.llmCache, r)
+	}
+	handler.CreateCredentialsHandler(services.resolver, r)
+	handler.CreateUsageHandler(services.meter, r)
+	handler.CreateRedactionHandler(services.redactor, r)
+	handler.CreateJobHandler(services.jobService, r)
+	handler.CreateCalendarHandler(services.jobService, r)
+	handler.CreateReportHandler(services.reportService, r)
+	handler.CreateResumeHandler(services.resumeService, r)
+	handler.CreateRankingHandler(services.rankingService, r)
+	handler.CreateCoverLetterHandler(services.coverLetterService, r)
+	handler.CreateSuggestionHandler(services.suggestionService, r)
+	handler.CreateInterviewPrepHandler(services.interviewPrepService, r)
+
+	// Initialize background tasks. They work on the server's own Notion
+	// database, so they need its credentials.
+	if services.resolver.HasDefault() {
+		startScheduler(services, cfg)
+	} else {
+		log.Println("No server credentials, reminders and digests are off")
+	}
+
+	// Apply the settings that can change without a restart.
+	reloader.OnReload(func(cfg *config.Config) {
+		llmLimit, readLimit, ipLimit, err := rateLimits(cfg.RateLimit)
+		if err != nil {
+			log.Printf("Failed to apply rate limits: %v", err)
+		} else {
+			llmLimiter.SetLimit(llmLimit)
+			readLimiter.SetLimit(readLimit)
+			ipLimiter.SetLimit(ipLimit)
+		}
+		services.meter.SetBudget(budget(cfg.LLM))
+		services.reminderService.SetConfig(reminderConfig(cfg.Reminders))
+		services.reportService.SetSchedule(digestSchedule(cfg.Digest))
+	})
 
 	// Health Check
 	r.GET("/health", func(c *gin.Context) {
@@ -32,30 +103,330 @@ func Initalize(r *gin.Engine) {
 	})
 }
 
+// services holds the dependencies shared by the server and the CLI
+// subcommands.
+type services struct {
+	httpClient           *http.Client
+	store                store.Store
+	llmCache             llmcache.Cache
+	meter                usage.Meter
+	redactor             redact.Redactor
+	resolver             tenant.Resolver
+	jobService           service.JobService
+	reminderService      service.ReminderService
+	reportService        service.ReportService
+	resumeService        service.ResumeService
+	rankingService       service.RankingService
+	coverLetterService   service.CoverLetterService
+	suggestionService    service.SuggestionService
+	interviewPrepService service.InterviewPrepService
+}
+
+// clientPoolSize is how many users' clients are kept for reuse.
+const clientPoolSize = 256
+
+func createServices(cfg *config.Config) (*services, error) {
+	httpClient := &http.Client{}
+
+	var sealer tenant.Sealer
+	if cfg.CredentialsKey != "" {
+		var err error
+		sealer, err = tenant.NewSealer(cfg.CredentialsKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credentialsKey (CREDENTIALS_KEY): %w", err)
+		}
+	}
+
+	// API keys come from the encrypted secrets file when stored there, and
+	// from the configuration otherwise.
+	secretStore, err := secrets.CreateStore(cfg.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open secrets: %w", err)
+	}
+
+	// The server's own credentials are optional when users can bring theirs,
+	// in a header or in a profile kept in the secrets file.
+	notionClient, llmProvider, err := client.CreateClients(httpClient, cfg, secretStore)
+	if err != nil {
+		if !errors.Is(err, client.ErrKeyNotSet) || (sealer == nil && !secretStore.Writable()) {
+			return nil, fmt.Errorf("Failed to create clients: %w", err)
+		}
+		log.Printf("Serving only requests with their own credentials: %v", err)
+	}
+	hasDefault := err == nil
+	if hasDefault && cfg.Notion.DatabaseID == "" {
+		return nil, errors.New("notion.databaseId (NOTION_DATABASE_ID) is required with the server's own Notion key")
+	}
+
+	// Calls go to the clients of the request's credentials when it has any.
+	pool := client.NewPool(httpClient, clientPoolSize)
+	notionClient = client.NewTenantNotionClient(notionClient, pool)
+	llmProvider = client.NewTenantGroqProvider(llmProvider, pool)
+
+	jobStore, err := store.NewFileStore(cfg.StorePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open store: %w", err)
+	}
+
+	notifiers, err := notify.CreateNotifiers(httpClient, cfg.Notify)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create notifiers: %w", err)
+	}
+
+	taxonomy, err := skills.LoadTaxonomy(cfg.SkillsTaxonomyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	skillMatcher, err := skills.NewMatcher(taxonomy)
+	if err != nil {
+		return nil, err
+	}
+
+	prompts, err := prompt.NewRegistry(cfg.LLM.PromptsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range prompts.List() {
+		if info.Override {
+			log.Printf("Using prompt override %s version %s", info.Name, info.Version)
+		}
+	}
+
+	llmCache, err := createLLMCache(cfg.LLM)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every LLM call goes through the meter, so it is counted and the budget
+	// applies.
+	meter, err := createMeter(jobStore, cfg.LLM, cfg.Records)
+	if err != nil {
+		return nil, err
+	}
+	llmProvider = meter.Wrap(llmProvider)
+
+	fallbacks, err := createFallbacks(httpClient, cfg, secretStore, llmProvider, meter)
+	if err != nil {
+		return nil, err
+	}
+
+	// tokenLimitsPath replaces the built-in context window sizes.
+	limits, err := tokens.LoadLimits(cfg.LLM.TokenLimitsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	redactKinds, err := redact.ParseKinds(cfg.LLM.PIIRedact)
+	if err != nil {
+		return nil, fmt.Errorf("invalid llm.piiRedact (PII_REDACT): %w", err)
+	}
+	redactionLog := store.NewFileLog(filepath.Join(cfg.Records.Dir, "redactions"), cfg.Records.RetentionDays)
+	if err := moveToLog(jobStore, "redactions", redactionLog); err != nil {
+		return nil, err
+	}
+	redactor := redact.NewRedactor(redactKinds, redactionLog)
+
+	resolver := tenant.NewResolver(sealer, secretStore, hasDefault)
+
+	llm := service.LLM{
+		Provider:  llmProvider,
+		Cache:     llmCache,
+		Prompts:   prompts,
+		Tokens:    tokens.NewEstimator(limits),
+		Fallbacks: fallbacks,
+		Redactor:  redactor,
+	}
+
+	resumeService := service.NewResumeService(llmProvider, jobStore, prompts, redactor)
+	jobService := service.NewJobService(cfg.Notion, notionClient, llm, jobStore, resumeService, skillMatcher)
+	reminderService := service.NewReminderService(jobService, jobStore, notifiers, reminderConfig(cfg.Reminders))
+	reportService := service.NewReportService(jobService, reminderService, jobStore, notifiers, digestSchedule(cfg.Digest))
+	rankingService := service.NewRankingService(jobService, resumeService, skillMatcher, prompts)
+	coverLetterService := service.NewCoverLetterService(llmProvider, jobStore, prompts, redactor, jobService, resumeService)
+	suggestionService := service.NewSuggestionService(llmProvider, prompts, redactor, jobService, resumeService, skillMatcher)
+	interviewPrepService := service.NewInterviewPrepService(llmProvider, jobStore, prompts, redactor, jobService, resumeService)
+
+	if cfg.InterviewPrepAuto {
+		jobService.OnStatusChange(interviewPrepService.HandleStatusChange)
+	}
+
+	return &services{
+		httpClient:           httpClient,
+		store:                jobStore,
+		llmCache:             llmCache,
+		meter:                meter,
+		redactor:             redactor,
+		resolver:             resolver,
+		jobService:           jobService,
+		reminderService:      reminderService,
+		reportService:        reportService,
+		resumeService:        resumeService,
+		rankingService:       rankingService,
+		coverLetterService:   coverLetterService,
+		suggestionService:    suggestionService,
+		interviewPrepService: interviewPrepService,
+	}, nil
+}
+
+// createAuthenticator checks API keys against cfg.KeysPath, managed with
+// `server keys`, and signs access tokens with cfg.TokenSecret.
+func createAuthenticator(cfg config.Auth) auth.Authenticator {
+	return auth.NewAuthenticator(cfg.KeysPath, []byte(cfg.TokenSecret))
+}
+
+// rateLimits reads the per-client limits of LLM routes and of all other
+// routes, and the per-IP limit of unauthenticated requests.
+func rateLimits(cfg config.RateLimit) (llm ratelimit.Limit, read ratelimit.Limit, ip ratelimit.Limit, err error) {
+	if llm, err = ratelimit.ParseLimit(cfg.LLM); err != nil {
+		return llm, read, ip, fmt.Errorf("invalid rateLimit.llm: %w", err)
+	}
+	if read, err = ratelimit.ParseLimit(cfg.Read); err != nil {
+		return llm, read, ip, fmt.Errorf("invalid rateLimit.read: %w", err)
+	}
+	if ip, err = ratelimit.ParseLimit(cfg.IP); err != nil {
+		return llm, read, ip, fmt.Errorf("invalid rateLimit.ip: %w", err)
+	}
+	return llm, read, ip, nil
+}
+
+// createLLMCache creates the cache for extraction and comparison replies,
+// or returns nil when cfg.CacheSize is 0.
+func createLLMCache(cfg config.LLM) (llmcache.Cache, error) {
+	if cfg.CacheSize == 0 {
+		return nil, nil
+	}
+	return llmcache.NewCache(cfg.CacheSize, cfg.CacheTTL.Duration, cfg.CacheDir)
+}
+
+// createMeter creates the meter that records usage under records.dir, moving
+// there the usage that older versions kept in jobStore. cfg.PricesPath
+// replaces the built-in price table.
+func createMeter(jobStore store.Store, cfg config.LLM, records config.Records) (usage.Meter, error) {
+	prices, err := usage.LoadPrices(cfg.PricesPath)
+	if err != nil {
+		return nil, err
+	}
+	usageLog := store.NewFileLog(filepath.Join(records.Dir, "usage"), records.RetentionDays)
+	if err := moveToLog(jobStore, "usage", usageLog); err != nil {
+		return nil, err
+	}
+	return usage.NewMeter(usageLog, prices, budget(cfg)), nil
+}
+
+// moveToLog appends the records that older versions kept in collection of
+// jobStore, a list per date, to records, and deletes them from jobStore.
+func moveToLog(jobStore store.Store, collection string, records store.Log) error {
+	days, err := jobStore.List(collection)
+	if err != nil {
+		return err
+	}
+	for date, raw := range days {
+		day, err := time.ParseInLocation(time.DateOnly, date, time.Local)
+		if err != nil {
+			return fmt.Errorf("error moving %s of %s: %w", collection, date, err)
+		}
+		var entries []json.RawMessage
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return fmt.Errorf("error moving %s of %s: %w", collection, date, err)
+		}
+		for _, entry := range entries {
+			if err := records.Append(day, entry); err != nil {
+				return fmt.Errorf("error moving %s of %s: %w", collection, date, err)
+			}
+		}
+		if err := jobStore.Delete(collection, date); err != nil {
+			return fmt.Errorf("error moving %s of %s: %w", collection, date, err)
+		}
+	}
+	return nil
+}
+
+func budget(cfg config.LLM) usage.Budget {
+	return usage.Budget{Monthly: cfg.MonthlyBudget, Action: cfg.BudgetAction}
+}
+
+// createFallbacks resolves the model fallback chains of extraction and
+// comparison. llm.fallbacksPath replaces the built-in chains; chains may
+// name the groq provider, and the openai provider when openai.baseUrl is
+// set. The openai provider has only the server's key, so requests with
+// their own credentials skip it.
+func createFallbacks(httpClient *http.Client, cfg *config.Config, secretStore secrets.Store, groqProvider client.LLMProvider, meter usage.Meter) (*fallback.Chains, error) {
+	providers := map[string]client.LLMProvider{groqProvider.Name(): groqProvider}
+	if cfg.OpenAI.BaseURL != "" {
+		openAIProvider, err := client.CreateOpenAIClient(httpClient, cfg.OpenAI, secretStore)
+		if err != nil {
+			return nil, err
+		}
+		providers[openAIProvider.Name()] = client.NewServerProvider(meter.Wrap(openAIProvider))
+	}
+
+	chains, err := fallback.LoadConfig(cfg.LLM.FallbacksPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return fallback.NewChains(chains, providers, prompt.ExtractJob.Name(), prompt.CompareJobPosting.Name())
+}
+
+// loadConfig reads the configuration of the CLI subcommands, which take no
+// server flags; use CONFIG_FILE to point them at a configuration file.
+func loadConfig() *config.Config {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return cfg
+}
+
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Error loading .env file")
 	}
 
-	mode := os.Getenv("MODE")
-	if mode == "release" {
-		gin.SetMode(gin.ReleaseMode)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "keys":
+			runKeys(os.Args[2:])
+			return
+		case "secrets":
+			runSecrets(os.Args[2:])
+			return
+		}
 	}
 
-	log.Println("Running in", gin.Mode(), "mode")
+	args := os.Args[1:]
+	cfg, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.Mode != "" {
+		gin.SetMode(cfg.Mode)
+	}
 
-	router := gin.Default()
+	log.Println("Running in", gin.Mode(), "mode")
 
-	Initalize(router)
+	router := gin.New()
+	router.Use(handler.Logger(), gin.Recovery())
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8000"
-	}
+	// SIGHUP reloads the settings that can change without a restart.
+	reloader := config.NewReloader(cfg, args)
+	Initalize(router, cfg, reloader)
+	reloader.Watch()
 
-	log.Println("Server starting on port", port)
+	log.Println("Server starting on port", cfg.Port)
 
-	if err := router.Run(":" + port); err != nil {
+	if err := router.Run(":" + strconv.Itoa(cfg.Port)); err != nil {
 		log.Fatal("Failed to start server: ", err)
 	}
 }
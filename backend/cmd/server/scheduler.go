@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"job-parser-backend/internal/config"
+	"job-parser-backend/internal/scheduler"
+	"job-parser-backend/internal/service"
+	"time"
+)
+
+// startScheduler registers the background tasks that cfg enables and starts
+// running them.
+func startScheduler(services *services, cfg *config.Config) {
+	tasks := scheduler.NewScheduler()
+
+	if cfg.Reminders.Enabled {
+		tasks.Every("follow-up reminders", cfg.Reminders.Interval.Duration, services.reminderService.SendDueReminders)
+	}
+
+	if cfg.Digest.Enabled {
+		tasks.Every("weekly digest", time.Hour, services.reportService.SendWeeklyDigest)
+	}
+
+	tasks.Start(context.Background())
+}
+
+func reminderConfig(cfg config.Reminders) service.ReminderConfig {
+	return service.ReminderConfig{
+		Threshold: time.Duration(cfg.ThresholdDays) * 24 * time.Hour,
+		Statuses:  cfg.Statuses,
+	}
+}
+
+func digestSchedule(cfg config.Digest) service.DigestSchedule {
+	return service.DigestSchedule{Weekday: cfg.Weekday.Weekday, Hour: cfg.Hour}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"job-parser-backend/internal/secrets"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// runSecrets implements `server secrets`, which manages the encrypted
+// secrets file. Values are read from stdin so they stay out of the shell
+// history.
+//
+//	server secrets genkey
+//	echo "$KEY" | server secrets set GROQ_API_KEY
+//	server secrets list
+//	server secrets delete GROQ_API_KEY
+//	server secrets rotate
+//
+// To rotate the master key, make the new key secrets.masterKey, move the old
+// one to secrets.previousMasterKeys, run rotate, then drop the old key.
+func runSecrets(args []string) {
+	if len(args) == 0 {
+		log.Fatal("Usage: server secrets genkey|set|list|delete|rotate")
+	}
+	if args[0] == "genkey" {
+		fmt.Println(secrets.GenerateKey())
+		return
+	}
+
+	secretStore, err := secrets.CreateStore(loadConfig().Secrets)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch args[0] {
+	case "set":
+		if len(args) != 2 {
+			log.Fatal("Usage: server secrets set <name> < value")
+		}
+		value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		value = strings.TrimRight(value, "\r\n")
+		if value == "" {
+			log.Fatal("No value on stdin: ", err)
+		}
+		if err := secretStore.Put(args[1], value); err != nil {
+			log.Fatal("Failed to store secret: ", err)
+		}
+		fmt.Fprintf(os.Stderr, "Stored %s\n", args[1])
+
+	case "list":
+		infos, err := secretStore.List()
+		if err != nil {
+			log.Fatal("Failed to list secrets: ", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tMASTER KEY\tUPDATED")
+		for _, info := range infos {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", info.Name, info.MasterKeyID, info.UpdatedAt)
+		}
+		w.Flush()
+
+	case "delete":
+		if len(args) != 2 {
+			log.Fatal("Usage: server secrets delete <name>")
+		}
+		if err := secretStore.Delete(args[1]); err != nil {
+			log.Fatal("Failed to delete secret: ", err)
+		}
+		fmt.Fprintf(os.Stderr, "Deleted %s\n", args[1])
+
+	case "rotate":
+		rotated, err := secretStore.Rotate()
+		if err != nil {
+			log.Fatal("Failed to rotate secrets: ", err)
+		}
+		fmt.Fprintf(os.Stderr, "Re-encrypted %d data keys with the current master key\n", rotated)
+
+	default:
+		log.Fatalf("Unknown secrets command %q, expected genkey, set, list, delete or rotate", args[0])
+	}
+}
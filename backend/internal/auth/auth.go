@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnauthenticated = errors.New("missing or invalid API key or token")
+	ErrKeyNotFound     = errors.New("API key not found")
+	ErrScopeNotGranted = errors.New("scope not granted to this API key")
+)
+
+const (
+	keyPrefix   = "jpk_"
+	tokenPrefix = "jpt_"
+
+	// DefaultTokenTTL and MaxTokenTTL bound how long an access token lives.
+	DefaultTokenTTL = 15 * time.Minute
+	MaxTokenTTL     = time.Hour
+)
+
+// Authenticator checks API keys and the access tokens issued for them.
+//
+// An API key looks like jpk_<id>_<secret>; only a SHA-256 hash of the
+// secret is stored. An access token is jpt_<claims>.<signature>, signed
+// with HMAC-SHA256, and is refused once it expires or its key is revoked.
+type Authenticator interface {
+	CreateKey(name string, user string, scopes []string) (*model.APIKey, string, error)
+	ListKeys() ([]model.APIKey, error)
+	RevokeKey(id string) error
+	Authenticate(credential string) (*Principal, error)
+	IssueToken(principal *Principal, scopes []string, ttl time.Duration) (*model.AccessToken, error)
+}
+
+type authenticator struct {
+	keys   *keyring
+	secret []byte
+}
+
+// claims is the signed body of an access token.
+type claims struct {
+	KeyID   string   `json:"kid"`
+	User    string   `json:"sub,omitempty"`
+	Scopes  []string `json:"scopes"`
+	Expires int64    `json:"exp"`
+}
+
+// NewAuthenticator reads API keys from the file at keysPath and signs
+// tokens with secret. An empty secret is replaced by a random one, so tokens
+// do not outlive the process.
+func NewAuthenticator(keysPath string, secret []byte) Authenticator {
+	if len(secret) == 0 {
+		secret = randomBytes(32)
+	}
+
+	return &authenticator{keys: &keyring{path: keysPath}, secret: secret}
+}
+
+// CreateKey stores a new key and returns it with the full key string, which
+// is shown once and cannot be recovered later.
+func (a *authenticator) CreateKey(name string, user string, scopes []string) (*model.APIKey, string, error) {
+	// IDs are hex so the first underscore always ends them.
+	id := hex.EncodeToString(randomBytes(6))
+	secret := base64.RawURLEncoding.EncodeToString(randomBytes(24))
+
+	key := model.APIKey{
+		ID:        id,
+		Name:      name,
+		User:      user,
+		Scopes:    scopes,
+		Hash:      hashSecret(secret),
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+
+	err := a.keys.update(func(keys map[string]model.APIKey) error {
+		keys[id] = key
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &key, keyPrefix + id + "_" + secret, nil
+}
+
+// ListKeys returns every key, revoked ones included, oldest first.
+func (a *authenticator) ListKeys() ([]model.APIKey, error) {
+	keys, err := a.keys.list()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt < keys[j].CreatedAt })
+	return keys, nil
+}
+
+// RevokeKey disables a key and every token issued for it.
+func (a *authenticator) RevokeKey(id string) error {
+	return a.keys.update(func(keys map[string]model.APIKey) error {
+		key, ok := keys[id]
+		if !ok {
+			return ErrKeyNotFound
+		}
+		if key.RevokedAt == "" {
+			key.RevokedAt = time.Now().Format(time.RFC3339)
+			keys[id] = key
+		}
+		return nil
+	})
+}
+
+func (a *authenticator) Authenticate(credential string) (*Principal, error) {
+	switch {
+	case strings.HasPrefix(credential, keyPrefix):
+		return a.authenticateKey(strings.TrimPrefix(credential, keyPrefix))
+	case strings.HasPrefix(credential, tokenPrefix):
+		return a.authenticateToken(strings.TrimPrefix(credential, tokenPrefix))
+	default:
+		return nil, ErrUnauthenticated
+	}
+}
+
+func (a *authenticator) authenticateKey(credential string) (*Principal, error) {
+	id, secret, ok := strings.Cut(credential, "_")
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	key, err := a.activeKey(id)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.Hash)) != 1 {
+		return nil, ErrUnauthenticated
+	}
+
+	return &Principal{KeyID: key.ID, User: key.User, Scopes: key.Scopes}, nil
+}
+
+func (a *authenticator) authenticateToken(credential string) (*Principal, error) {
+	body, signature, ok := strings.Cut(credential, ".")
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	expected := a.sign(body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, ErrUnauthenticated
+	}
+
+	bytes, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	var c claims
+	if err := json.Unmarshal(bytes, &c); err != nil {
+		return nil, ErrUnauthenticated
+	}
+	if time.Now().Unix() >= c.Expires {
+		return nil, ErrUnauthenticated
+	}
+
+	// The key is checked again so revoking it ends its tokens at once.
+	if _, err := a.activeKey(c.KeyID); err != nil {
+		return nil, err
+	}
+
+	return &Principal{KeyID: c.KeyID, User: c.User, Scopes: c.Scopes, Token: true}, nil
+}
+
+func (a *authenticator) activeKey(id string) (*model.APIKey, error) {
+	key, ok, err := a.keys.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || key.RevokedAt != "" {
+		return nil, ErrUnauthenticated
+	}
+	return &key, nil
+}
+
+// IssueToken signs a token for principal's key, limited to scopes (all of
+// the key's scopes when empty) and ttl, capped at MaxTokenTTL.
+func (a *authenticator) IssueToken(principal *Principal, scopes []string, ttl time.Duration) (*model.AccessToken, error) {
+	if len(scopes) == 0 {
+		scopes = principal.Scopes
+	}
+	for _, scope := range scopes {
+		if !principal.Allows(scope) {
+			return nil, fmt.Errorf("%w: %s", ErrScopeNotGranted, scope)
+		}
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultTokenTTL
+	}
+	ttl = min(ttl, MaxTokenTTL)
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+
+	bytes, err := json.Marshal(claims{
+		KeyID:   principal.KeyID,
+		User:    principal.User,
+		Scopes:  slices.Clone(scopes),
+		Expires: expires.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding token: %w", err)
+	}
+	body := base64.RawURLEncoding.EncodeToString(bytes)
+
+	return &model.AccessToken{
+		Token:     tokenPrefix + body + "." + a.sign(body),
+		Scopes:    scopes,
+		ExpiresAt: expires.Format(time.RFC3339),
+	}, nil
+}
+
+func (a *authenticator) sign(body string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// hashSecret hashes a key secret. Secrets are long and random, so a plain
+// SHA-256 is enough; there is nothing to brute-force.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic("crypto/rand failed: " + err.Error())
+	}
+	return b
+}
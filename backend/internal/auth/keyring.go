@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// keyring is the API keys file, shared by the server and the keys CLI. The
+// server only reads it and reloads it when the CLI changes it, so new and
+// revoked keys take effect without a restart.
+type keyring struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	keys    map[string]model.APIKey
+}
+
+func (k *keyring) get(id string) (model.APIKey, bool, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	info, err := os.Stat(k.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return model.APIKey{}, false, nil
+	}
+	if err != nil {
+		return model.APIKey{}, false, fmt.Errorf("error reading API keys: %w", err)
+	}
+
+	if k.keys == nil || !info.ModTime().Equal(k.modTime) {
+		keys, err := k.read()
+		if err != nil {
+			return model.APIKey{}, false, err
+		}
+		k.keys, k.modTime = keys, info.ModTime()
+	}
+
+	key, ok := k.keys[id]
+	return key, ok, nil
+}
+
+func (k *keyring) read() (map[string]model.APIKey, error) {
+	keys := make(map[string]model.APIKey)
+
+	bytes, err := os.ReadFile(k.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading API keys: %w", err)
+	}
+
+	if len(bytes) > 0 {
+		if err := json.Unmarshal(bytes, &keys); err != nil {
+			return nil, fmt.Errorf("error decoding API keys: %w", err)
+		}
+	}
+	return keys, nil
+}
+
+// update rewrites the file with the result of fn, replacing it atomically
+// so the server never reads a partial file.
+func (k *keyring) update(fn func(keys map[string]model.APIKey) error) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	keys, err := k.read()
+	if err != nil {
+		return err
+	}
+	if err := fn(keys); err != nil {
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding API keys: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(k.path), 0o700); err != nil {
+		return fmt.Errorf("error creating API keys directory: %w", err)
+	}
+
+	tmp := k.path + ".tmp"
+	if err := os.WriteFile(tmp, bytes, 0o600); err != nil {
+		return fmt.Errorf("error writing API keys: %w", err)
+	}
+	if err := os.Rename(tmp, k.path); err != nil {
+		return fmt.Errorf("error replacing API keys: %w", err)
+	}
+
+	k.keys = nil
+	return nil
+}
+
+func (k *keyring) list() ([]model.APIKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	keys, err := k.read()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]model.APIKey, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, key)
+	}
+	return list, nil
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// ScopeRead allows reading jobs, resumes and reports.
+	ScopeRead = "read"
+	// ScopeWrite allows saving and updating jobs, resumes and settings.
+	ScopeWrite = "write"
+	// ScopeCompare allows the routes that call a model on demand.
+	ScopeCompare = "compare"
+	// ScopeAdmin allows everything, including server-wide reports.
+	ScopeAdmin = "admin"
+)
+
+var Scopes = []string{ScopeRead, ScopeWrite, ScopeCompare, ScopeAdmin}
+
+// ParseScopes reads a comma-separated scope list.
+func ParseScopes(value string) ([]string, error) {
+	var scopes []string
+	for _, scope := range strings.Split(value, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if !slices.Contains(Scopes, scope) {
+			return nil, fmt.Errorf("unknown scope %q, expected one of %s", scope, strings.Join(Scopes, ", "))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+	return scopes, nil
+}
+
+// Principal is who a request was authenticated as.
+type Principal struct {
+	KeyID  string
+	User   string
+	Scopes []string
+	// Token is set when the request used an access token rather than the
+	// API key itself.
+	Token bool
+}
+
+// Allows reports whether the principal has scope; admin has every scope.
+func (p *Principal) Allows(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal of ctx, or nil when the request was not
+// authenticated.
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,213 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"job-parser-backend/internal/model"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+// eventDurations is how long a timed event lasts when no end is recorded.
+var eventDurations = map[string]time.Duration{
+	model.EventInterview: time.Hour,
+	model.EventDeadline:  30 * time.Minute,
+	model.EventFollowUp:  30 * time.Minute,
+}
+
+var eventTitles = map[string]string{
+	model.EventInterview: "Interview",
+	model.EventDeadline:  "Application deadline",
+	model.EventFollowUp:  "Follow up",
+}
+
+// WriteICS renders the events of jobs as an RFC 5545 VCALENDAR. Timed events
+// are converted to UTC so the feed needs no VTIMEZONE definitions, and each
+// event's UID is derived from the job ID and event type so subscribed
+// calendars update entries in place instead of duplicating them.
+func WriteICS(w io.Writer, jobs []model.Job, now time.Time) error {
+	cal := &icsWriter{w: w}
+
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:-//job-parser//Job Parser//EN")
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("METHOD:PUBLISH")
+	cal.line("X-WR-CALNAME:Job Applications")
+	cal.line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	cal.line("X-PUBLISHED-TTL:PT1H")
+
+	stamp := now.UTC().Format(dateTimeLayout)
+
+	for _, job := range jobs {
+		for _, event := range job.Events {
+			start, end, allDay, err := eventTimes(event)
+			if err != nil {
+				// One malformed date in Notion shouldn't take the whole feed down.
+				continue
+			}
+
+			cal.line("BEGIN:VEVENT")
+			cal.line("UID:" + job.ID + "-" + event.Type + "@job-parser")
+			cal.line("DTSTAMP:" + stamp)
+			if allDay {
+				cal.line("DTSTART;VALUE=DATE:" + start.Format(dateLayout))
+				cal.line("DTEND;VALUE=DATE:" + end.Format(dateLayout))
+			} else {
+				cal.line("DTSTART:" + start.UTC().Format(dateTimeLayout))
+				cal.line("DTEND:" + end.UTC().Format(dateTimeLayout))
+			}
+			cal.line("SUMMARY:" + escapeText(summary(job, event)))
+			cal.line("DESCRIPTION:" + escapeText(description(job)))
+			if job.URL != "" {
+				cal.line("URL:" + job.URL)
+			}
+			cal.line("CATEGORIES:" + escapeText(eventTitles[event.Type]))
+			cal.line("TRANSP:TRANSPARENT")
+			cal.line("END:VEVENT")
+		}
+	}
+
+	cal.line("END:VCALENDAR")
+
+	return cal.err
+}
+
+// eventTimes resolves an event to concrete start and end instants. Date-only
+// values become all-day events with an exclusive end date, as RFC 5545
+// requires.
+func eventTimes(event model.JobEvent) (time.Time, time.Time, bool, error) {
+	location := time.UTC
+	if event.TimeZone != "" {
+		loaded, err := time.LoadLocation(event.TimeZone)
+		if err != nil {
+			return time.Time{}, time.Time{}, false, fmt.Errorf("unknown time zone %q: %w", event.TimeZone, err)
+		}
+		location = loaded
+	}
+
+	start, allDay, err := parseDate(event.Start, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+
+	if event.End != "" {
+		end, _, err := parseDate(event.End, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, false, err
+		}
+		if allDay {
+			end = end.AddDate(0, 0, 1)
+		}
+		if end.After(start) {
+			return start, end, allDay, nil
+		}
+	}
+
+	if allDay {
+		return start, start.AddDate(0, 0, 1), true, nil
+	}
+
+	return start, start.Add(eventDurations[event.Type]), false, nil
+}
+
+// parseDate accepts the formats Notion returns: a plain date, a date-time
+// with an offset, or a date-time without one that is read in location.
+func parseDate(value string, location *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, location); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", value, location); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04", value, location); err == nil {
+		return t, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("unrecognized date %q", value)
+}
+
+func summary(job model.Job, event model.JobEvent) string {
+	title := eventTitles[event.Type]
+	if title == "" {
+		title = event.Type
+	}
+
+	switch {
+	case job.Title != "" && job.Company != "":
+		return fmt.Sprintf("%s: %s at %s", title, job.Title, job.Company)
+	case job.Title != "":
+		return fmt.Sprintf("%s: %s", title, job.Title)
+	case job.Company != "":
+		return fmt.Sprintf("%s: %s", title, job.Company)
+	default:
+		return title
+	}
+}
+
+func description(job model.Job) string {
+	var lines []string
+	if job.Status != "" {
+		lines = append(lines, "Status: "+job.Status)
+	}
+	if job.Country != "" {
+		lines = append(lines, "Country: "+job.Country)
+	}
+	if job.URL != "" {
+		lines = append(lines, job.URL)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escapeText escapes a TEXT property value per RFC 5545 section 3.3.11.
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line writes a content line terminated by CRLF, folding it so no physical
+// line exceeds 75 octets and no UTF-8 sequence is split across a fold.
+func (c *icsWriter) line(content string) {
+	if c.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts toward the limit.
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+
+	_, c.err = io.WriteString(c.w, b.String())
+}
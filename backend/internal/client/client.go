@@ -1,17 +1,26 @@
 package client
 
 import (
+	"errors"
 	"fmt"
+	"job-parser-backend/internal/config"
+	"job-parser-backend/internal/secrets"
 	"net/http"
 )
 
-func CreateClients(httpClient *http.Client) (NotionClient, GroqClient, error) {
-	notionClient, err := CreateNotionClient(httpClient)
+// ErrKeyNotSet is returned when a client's API key is neither stored nor
+// configured.
+var ErrKeyNotSet = errors.New("API key is not set")
+
+// CreateClients creates the server's own clients with the keys from
+// secretStore, or else from cfg.
+func CreateClients(httpClient *http.Client, cfg *config.Config, secretStore secrets.Store) (NotionClient, LLMProvider, error) {
+	notionClient, err := CreateNotionClient(httpClient, cfg.Notion, secretStore)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error creating Notion client: %w", err)
 	}
 
-	groqClient, err := CreateGroqClient(httpClient)
+	groqClient, err := CreateGroqClient(httpClient, cfg.Groq, secretStore)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error creating Groq client: %w", err)
 	}
@@ -0,0 +1,13 @@
+package client
+
+import (
+	"context"
+	"job-parser-backend/internal/model"
+)
+
+// LLMProvider sends chat completion requests. Bodies and responses use the
+// OpenAI format, which Groq and most local model servers accept as well.
+type LLMProvider interface {
+	Name() string
+	ChatCompletion(ctx context.Context, body map[string]any) (*model.ChatResponse, error)
+}
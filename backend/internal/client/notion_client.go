@@ -2,15 +2,21 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"job-parser-backend/internal/config"
 	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/secrets"
 	"net/http"
-	"os"
 )
 
+// ErrNotFound is returned when Notion has no page or database with the
+// requested ID, or does not share it with the integration.
+var ErrNotFound = errors.New("not found in Notion")
+
 type notionClient struct {
 	apiKey     string
 	baseURL    string
@@ -19,38 +25,52 @@ type notionClient struct {
 }
 
 type NotionClient interface {
-	request(requestType string, url string, body map[string]any, responseFormat any) error
-	GetNotionPage(pageID string, body map[string]any) (*model.NotionPage, error)
-	GetNotionDatabase(databaseID string, body map[string]any) (*model.NotionResponse, error)
-	UpdateNotionPage(pageID string, body map[string]any) (*model.NotionPage, error)
-	CreateNotionPage(databaseID string, body map[string]any) (*model.NotionPage, error)
+	request(ctx context.Context, requestType string, url string, body map[string]any, responseFormat any) error
+	GetNotionPage(ctx context.Context, pageID string, body map[string]any) (*model.NotionPage, error)
+	GetNotionDatabase(ctx context.Context, databaseID string, body map[string]any) (*model.NotionResponse, error)
+	UpdateNotionPage(ctx context.Context, pageID string, body map[string]any) (*model.NotionPage, error)
+	CreateNotionPage(ctx context.Context, databaseID string, body map[string]any) (*model.NotionPage, error)
 }
 
-func CreateNotionClient(httpClient *http.Client) (NotionClient, error) {
-	notionApiKey := os.Getenv("NOTION_API_KEY")
+// CreateNotionClient creates a client with the NOTION_API_KEY secret, or
+// else the key in cfg.
+func CreateNotionClient(httpClient *http.Client, cfg config.Notion, secretStore secrets.Store) (NotionClient, error) {
+	notionApiKey, err := secretStore.Lookup("NOTION_API_KEY", cfg.APIKey)
+	if err != nil {
+		return nil, err
+	}
 
 	if notionApiKey == "" {
-		return nil, errors.New("Notion API key is not set")
+		return nil, fmt.Errorf("Notion %w", ErrKeyNotSet)
 	}
 
+	return NewNotionClient(notionApiKey, httpClient), nil
+}
+
+// NewNotionClient creates a client that acts with the given integration key.
+func NewNotionClient(apiKey string, httpClient *http.Client) NotionClient {
 	return &notionClient{
-		apiKey:     notionApiKey,
+		apiKey:     apiKey,
 		baseURL:    "https://api.notion.com/v1/",
 		httpClient: httpClient,
 		version:    "2022-06-28",
-	}, nil
+	}
 }
 
-func (c *notionClient) request(requestType string, url string, body map[string]any, responseFormat any) error {
+func (c *notionClient) request(ctx context.Context, requestType string, url string, body map[string]any, responseFormat any) error {
 
 	url = c.baseURL + url
 
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return fmt.Errorf("error marshalling request body: %w", err)
+	var requestBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error marshalling request body: %w", err)
+		}
+		requestBody = bytes.NewBuffer(bodyBytes)
 	}
 
-	request, err := http.NewRequest(requestType, url, bytes.NewBuffer(bodyBytes))
+	request, err := http.NewRequestWithContext(ctx, requestType, url, requestBody)
 	if err != nil {
 		return fmt.Errorf("error creating HTTP request: %w", err)
 	}
@@ -66,6 +86,10 @@ func (c *notionClient) request(requestType string, url string, body map[string]a
 
 	defer response.Body.Close()
 
+	if response.StatusCode == http.StatusNotFound {
+		body, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("%w: %s", ErrNotFound, string(body))
+	}
 	if response.StatusCode != 200 {
 		body, _ := io.ReadAll(response.Body)
 		return fmt.Errorf("Notion request returned status code %d with body: %s", response.StatusCode, string(body))
@@ -80,43 +104,43 @@ func (c *notionClient) request(requestType string, url string, body map[string]a
 	return nil
 }
 
-func (c *notionClient) GetNotionPage(pageID string, body map[string]any) (*model.NotionPage, error) {
+func (c *notionClient) GetNotionPage(ctx context.Context, pageID string, body map[string]any) (*model.NotionPage, error) {
 	url := fmt.Sprintf("pages/%s", pageID)
 	var response model.NotionPage
-	err := c.request("POST", url, body, &response)
+	err := c.request(ctx, "GET", url, body, &response)
 	if err != nil {
 		return nil, err
 	}
 	return &response, nil
 }
 
-func (c *notionClient) GetNotionDatabase(databaseID string, body map[string]any) (*model.NotionResponse, error) {
+func (c *notionClient) GetNotionDatabase(ctx context.Context, databaseID string, body map[string]any) (*model.NotionResponse, error) {
 	url := fmt.Sprintf("databases/%s/query", databaseID)
 	var response model.NotionResponse
-	err := c.request("POST", url, body, &response)
+	err := c.request(ctx, "POST", url, body, &response)
 	if err != nil {
 		return nil, err
 	}
 	return &response, nil
 }
 
-func (c *notionClient) UpdateNotionPage(pageID string, body map[string]any) (*model.NotionPage, error) {
+func (c *notionClient) UpdateNotionPage(ctx context.Context, pageID string, body map[string]any) (*model.NotionPage, error) {
 	url := fmt.Sprintf("pages/%s", pageID)
 	var response model.NotionPage
-	err := c.request("PATCH", url, body, &response)
+	err := c.request(ctx, "PATCH", url, body, &response)
 	if err != nil {
 		return nil, err
 	}
 	return &response, nil
 }
 
-func (c *notionClient) CreateNotionPage(databaseID string, body map[string]any) (*model.NotionPage, error) {
+func (c *notionClient) CreateNotionPage(ctx context.Context, databaseID string, body map[string]any) (*model.NotionPage, error) {
 	url := fmt.Sprintf("pages")
 	var response model.NotionPage
 
 	body["parent"] = map[string]string{"database_id": databaseID}
 
-	err := c.request("POST", url, body, &response)
+	err := c.request(ctx, "POST", url, body, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"job-parser-backend/internal/config"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/secrets"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const groqProviderName = "groq"
+
+// openAIClient talks to any OpenAI-compatible chat completions endpoint.
+type openAIClient struct {
+	name       string
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// CreateGroqClient creates a provider with the GROQ_API_KEY secret, or else
+// the key in cfg.
+func CreateGroqClient(httpClient *http.Client, cfg config.Groq, secretStore secrets.Store) (LLMProvider, error) {
+	groqAPIKey, err := secretStore.Lookup("GROQ_API_KEY", cfg.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if groqAPIKey == "" {
+		return nil, fmt.Errorf("Groq %w", ErrKeyNotSet)
+	}
+
+	return NewGroqClient(groqAPIKey, httpClient), nil
+}
+
+// NewGroqClient creates a Groq provider that acts with the given key.
+func NewGroqClient(apiKey string, httpClient *http.Client) LLMProvider {
+	return NewOpenAIClient(groqProviderName, "https://api.groq.com/openai/v1", apiKey, httpClient)
+}
+
+// CreateOpenAIClient creates a client for the OpenAI-compatible server at
+// cfg.BaseURL, e.g. a local model server. The OPENAI_API_KEY secret, or else
+// the key in cfg, is optional because local servers usually do not check it.
+func CreateOpenAIClient(httpClient *http.Client, cfg config.OpenAI, secretStore secrets.Store) (LLMProvider, error) {
+	if cfg.BaseURL == "" {
+		return nil, errors.New("OpenAI base URL not set")
+	}
+
+	apiKey, err := secretStore.Lookup("OPENAI_API_KEY", cfg.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOpenAIClient("openai", cfg.BaseURL, apiKey, httpClient), nil
+}
+
+// NewOpenAIClient creates a provider for the API at baseURL, which is the
+// part before /chat/completions.
+func NewOpenAIClient(name string, baseURL string, apiKey string, httpClient *http.Client) LLMProvider {
+	return &openAIClient{
+		name:       name,
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/") + "/chat/completions",
+		httpClient: httpClient,
+	}
+}
+
+func (c *openAIClient) Name() string {
+	return c.name
+}
+
+func (c *openAIClient) ChatCompletion(ctx context.Context, body map[string]any) (*model.ChatResponse, error) {
+	var response model.ChatResponse
+
+	start := time.Now()
+	if err := c.request(ctx, "POST", body, &response); err != nil {
+		return nil, fmt.Errorf("error making %s request: %w", c.name, err)
+	}
+	response.Latency = time.Since(start)
+
+	if len(response.Choices) == 0 || response.Choices[0].Message.Content == "" {
+		return nil, fmt.Errorf("empty or invalid response from %s API: no choices or content found", c.name)
+	}
+
+	return &response, nil
+}
+
+func (c *openAIClient) request(ctx context.Context, requestType string, body map[string]any, responseFormat any) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error marshalling request body: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, requestType, c.baseURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("error creating HTTP request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error sending HTTP request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("%s request failed with status %d: %s", c.name, response.StatusCode, string(bodyBytes))
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&responseFormat); err != nil {
+		return fmt.Errorf("error decoding response JSON: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/utils"
+	"os"
+	"sync"
+)
+
+var ErrNoRecording = errors.New("no recorded response for request")
+
+// recording is one line of a recordings file. Key identifies the request by
+// its model and messages, so any change to a prompt or input misses.
+type recording struct {
+	Key     string `json:"key"`
+	Model   string `json:"model"`
+	Content string `json:"content"`
+}
+
+type replayProvider struct {
+	responses map[string]string
+}
+
+// NewReplayProvider answers requests from a recordings file written by a
+// recording provider, so evaluations can be rerun offline and for free.
+func NewReplayProvider(path string) (LLMProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening recordings: %w", err)
+	}
+	defer file.Close()
+
+	responses := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r recording
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("error decoding recording: %w", err)
+		}
+		responses[r.Key] = r.Content
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading recordings: %w", err)
+	}
+
+	return &replayProvider{responses: responses}, nil
+}
+
+func (p *replayProvider) Name() string {
+	return "replay"
+}
+
+func (p *replayProvider) ChatCompletion(ctx context.Context, body map[string]any) (*model.ChatResponse, error) {
+	key, err := requestKey(body)
+	if err != nil {
+		return nil, err
+	}
+
+	content, ok := p.responses[key]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrNoRecording, key)
+	}
+
+	return chatResponse(content), nil
+}
+
+type recordingProvider struct {
+	provider LLMProvider
+	mu       sync.Mutex
+	file     *os.File
+}
+
+// NewRecordingProvider passes requests to provider and appends every
+// successful response to the recordings file at path.
+func NewRecordingProvider(provider LLMProvider, path string) (LLMProvider, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening recordings: %w", err)
+	}
+
+	return &recordingProvider{provider: provider, file: file}, nil
+}
+
+func (p *recordingProvider) Name() string {
+	return p.provider.Name()
+}
+
+func (p *recordingProvider) ChatCompletion(ctx context.Context, body map[string]any) (*model.ChatResponse, error) {
+	response, err := p.provider.ChatCompletion(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := requestKey(body)
+	if err != nil {
+		return nil, err
+	}
+
+	modelName, _ := body["model"].(string)
+	line, err := json.Marshal(recording{
+		Key:     key,
+		Model:   modelName,
+		Content: response.Choices[0].Message.Content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding recording: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.file.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("error writing recording: %w", err)
+	}
+
+	return response, nil
+}
+
+func requestKey(body map[string]any) (string, error) {
+	bytes, err := json.Marshal(map[string]any{
+		"model":    body["model"],
+		"messages": body["messages"],
+	})
+	if err != nil {
+		return "", fmt.Errorf("error encoding request key: %w", err)
+	}
+	return utils.ContentHash(string(bytes)), nil
+}
+
+func chatResponse(content string) *model.ChatResponse {
+	return &model.ChatResponse{
+		Choices: []model.ChatChoice{{Message: model.ChatMessage{Content: content}}},
+	}
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/tenant"
+	"net/http"
+	"sync"
+)
+
+// Pool hands out the clients of each user's credentials. Users get their
+// own client instances, so their keys, quotas and failures stay apart; the
+// most recently used are kept so their connections are reused.
+type Pool interface {
+	Notion(credentials tenant.Credentials) NotionClient
+	Groq(credentials tenant.Credentials) LLMProvider
+}
+
+type pooledClients struct {
+	fingerprint string
+	notion      NotionClient
+	groq        LLMProvider
+}
+
+type pool struct {
+	httpClient *http.Client
+	size       int
+	mu         sync.Mutex
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+// NewPool creates a pool that keeps the clients of up to size credentials.
+func NewPool(httpClient *http.Client, size int) Pool {
+	return &pool{
+		httpClient: httpClient,
+		size:       size,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (p *pool) Notion(credentials tenant.Credentials) NotionClient {
+	return p.clients(credentials).notion
+}
+
+func (p *pool) Groq(credentials tenant.Credentials) LLMProvider {
+	return p.clients(credentials).groq
+}
+
+func (p *pool) clients(credentials tenant.Credentials) *pooledClients {
+	fingerprint := credentials.Fingerprint()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if element, ok := p.entries[fingerprint]; ok {
+		p.order.MoveToFront(element)
+		return element.Value.(*pooledClients)
+	}
+
+	clients := &pooledClients{
+		fingerprint: fingerprint,
+		notion:      NewNotionClient(credentials.NotionAPIKey, p.httpClient),
+		groq:        NewGroqClient(credentials.GroqAPIKey, p.httpClient),
+	}
+	p.entries[fingerprint] = p.order.PushFront(clients)
+
+	for p.order.Len() > p.size {
+		oldest := p.order.Back()
+		p.order.Remove(oldest)
+		delete(p.entries, oldest.Value.(*pooledClients).fingerprint)
+	}
+
+	return clients
+}
+
+// tenantNotionClient sends each call with the credentials of its request.
+type tenantNotionClient struct {
+	fallback NotionClient
+	pool     Pool
+}
+
+// NewTenantNotionClient routes each call to the client of the request's
+// credentials, or to fallback, the server's own client, when it has none.
+// fallback is nil when the server has no credentials of its own.
+func NewTenantNotionClient(fallback NotionClient, pool Pool) NotionClient {
+	return &tenantNotionClient{fallback: fallback, pool: pool}
+}
+
+func (c *tenantNotionClient) client(ctx context.Context) (NotionClient, error) {
+	if credentials, ok := tenant.CredentialsFrom(ctx); ok {
+		return c.pool.Notion(credentials), nil
+	}
+	if c.fallback == nil {
+		return nil, tenant.ErrNoCredentials
+	}
+	return c.fallback, nil
+}
+
+func (c *tenantNotionClient) request(ctx context.Context, requestType string, url string, body map[string]any, responseFormat any) error {
+	client, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.request(ctx, requestType, url, body, responseFormat)
+}
+
+func (c *tenantNotionClient) GetNotionPage(ctx context.Context, pageID string, body map[string]any) (*model.NotionPage, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetNotionPage(ctx, pageID, body)
+}
+
+func (c *tenantNotionClient) GetNotionDatabase(ctx context.Context, databaseID string, body map[string]any) (*model.NotionResponse, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetNotionDatabase(ctx, databaseID, body)
+}
+
+func (c *tenantNotionClient) UpdateNotionPage(ctx context.Context, pageID string, body map[string]any) (*model.NotionPage, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.UpdateNotionPage(ctx, pageID, body)
+}
+
+func (c *tenantNotionClient) CreateNotionPage(ctx context.Context, databaseID string, body map[string]any) (*model.NotionPage, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateNotionPage(ctx, databaseID, body)
+}
+
+// tenantGroqProvider sends each completion with the Groq key of its request.
+type tenantGroqProvider struct {
+	fallback LLMProvider
+	pool     Pool
+}
+
+// NewTenantGroqProvider routes each completion to the Groq client of the
+// request's credentials, or to fallback when it has none. fallback is nil
+// when the server has no Groq key of its own.
+func NewTenantGroqProvider(fallback LLMProvider, pool Pool) LLMProvider {
+	return &tenantGroqProvider{fallback: fallback, pool: pool}
+}
+
+func (p *tenantGroqProvider) Name() string {
+	return groqProviderName
+}
+
+func (p *tenantGroqProvider) ChatCompletion(ctx context.Context, body map[string]any) (*model.ChatResponse, error) {
+	if credentials, ok := tenant.CredentialsFrom(ctx); ok {
+		return p.pool.Groq(credentials).ChatCompletion(ctx, body)
+	}
+	if p.fallback == nil {
+		return nil, tenant.ErrNoCredentials
+	}
+	return p.fallback.ChatCompletion(ctx, body)
+}
+
+// ErrServerOnly is returned by a server provider for a request that brought
+// its own credentials.
+var ErrServerOnly = errors.New("provider only acts with the server's own key")
+
+// serverProvider is a provider that always acts with the server's own key.
+type serverProvider struct {
+	LLMProvider
+}
+
+// NewServerProvider marks provider as acting only with the server's own key.
+// It refuses requests that bring their own credentials, which must never run
+// on the server owner's account.
+func NewServerProvider(provider LLMProvider) LLMProvider {
+	return &serverProvider{LLMProvider: provider}
+}
+
+func (p *serverProvider) ChatCompletion(ctx context.Context, body map[string]any) (*model.ChatResponse, error) {
+	if _, ok := tenant.CredentialsFrom(ctx); ok {
+		return nil, ErrServerOnly
+	}
+	return p.LLMProvider.ChatCompletion(ctx, body)
+}
+
+// ServesRequest reports whether provider can serve the request of ctx, which
+// a server provider cannot when the request brought its own credentials.
+func ServesRequest(ctx context.Context, provider LLMProvider) bool {
+	if _, ok := provider.(*serverProvider); !ok {
+		return true
+	}
+	_, ok := tenant.CredentialsFrom(ctx)
+	return !ok
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Config is the server's configuration. Each setting can come from the
+// configuration file under its yaml/toml key, from the environment variable
+// named by its env tag, or from a command-line flag named after its key path,
+// e.g. -llm.cacheSize. Settings tagged secret have no flag, so they stay out
+// of process listings.
+type Config struct {
+	// Mode is gin's mode: debug, release or test.
+	Mode      string `yaml:"mode" toml:"mode" env:"MODE"`
+	Port      int    `yaml:"port" toml:"port" env:"PORT"`
+	StorePath string `yaml:"storePath" toml:"storePath" env:"STORE_PATH"`
+	// CredentialsKey, a base64 AES-256 key, lets each request bring its own
+	// Notion and Groq credentials in a header.
+	CredentialsKey     string `yaml:"credentialsKey" toml:"credentialsKey" env:"CREDENTIALS_KEY" secret:"true"`
+	SkillsTaxonomyPath string `yaml:"skillsTaxonomyPath" toml:"skillsTaxonomyPath" env:"SKILLS_TAXONOMY_PATH"`
+	// InterviewPrepAuto builds a prep pack whenever a job moves to Interview.
+	InterviewPrepAuto bool `yaml:"interviewPrepAuto" toml:"interviewPrepAuto" env:"INTERVIEW_PREP_AUTO"`
+
+	Notion    Notion    `yaml:"notion" toml:"notion"`
+	Groq      Groq      `yaml:"groq" toml:"groq"`
+	OpenAI    OpenAI    `yaml:"openai" toml:"openai"`
+	LLM       LLM       `yaml:"llm" toml:"llm"`
+	Auth      Auth      `yaml:"auth" toml:"auth"`
+	RateLimit RateLimit `yaml:"rateLimit" toml:"rateLimit"`
+	Secrets   Secrets   `yaml:"secrets" toml:"secrets"`
+	Records   Records   `yaml:"records" toml:"records"`
+	Reminders Reminders `yaml:"reminders" toml:"reminders"`
+	Digest    Digest    `yaml:"digest" toml:"digest"`
+	Notify    Notify    `yaml:"notify" toml:"notify"`
+}
+
+// Notion holds the server's own Notion credentials. The API key may instead
+// be kept in the secrets file.
+type Notion struct {
+	APIKey     string `yaml:"apiKey" toml:"apiKey" env:"NOTION_API_KEY" secret:"true"`
+	DatabaseID string `yaml:"databaseId" toml:"databaseId" env:"NOTION_DATABASE_ID"`
+}
+
+type Groq struct {
+	APIKey string `yaml:"apiKey" toml:"apiKey" env:"GROQ_API_KEY" secret:"true"`
+}
+
+// OpenAI is an OpenAI-compatible server, e.g. a local model server, that
+// fallback chains may name. It is off without a BaseURL.
+type OpenAI struct {
+	BaseURL string `yaml:"baseUrl" toml:"baseUrl" env:"OPENAI_BASE_URL"`
+	APIKey  string `yaml:"apiKey" toml:"apiKey" env:"OPENAI_API_KEY" secret:"true"`
+}
+
+type LLM struct {
+	// CacheSize is the number of replies kept in memory, 0 to disable the
+	// cache; CacheTTL how long they are reused; and CacheDir, when set,
+	// where they are persisted across restarts.
+	CacheSize int      `yaml:"cacheSize" toml:"cacheSize" env:"LLM_CACHE_SIZE"`
+	CacheTTL  Duration `yaml:"cacheTtl" toml:"cacheTtl" env:"LLM_CACHE_TTL"`
+	CacheDir  string   `yaml:"cacheDir" toml:"cacheDir" env:"LLM_CACHE_DIR"`
+	// MonthlyBudget caps each user's monthly spend, 0 for no cap, after
+	// which BudgetAction either refuses calls or downgrades them to cheaper
+	// models.
+	MonthlyBudget   float64 `yaml:"monthlyBudget" toml:"monthlyBudget" env:"LLM_MONTHLY_BUDGET"`
+	BudgetAction    string  `yaml:"budgetAction" toml:"budgetAction" env:"LLM_BUDGET_ACTION"`
+	PricesPath      string  `yaml:"pricesPath" toml:"pricesPath" env:"LLM_PRICES_PATH"`
+	FallbacksPath   string  `yaml:"fallbacksPath" toml:"fallbacksPath" env:"LLM_FALLBACKS_PATH"`
+	TokenLimitsPath string  `yaml:"tokenLimitsPath" toml:"tokenLimitsPath" env:"TOKEN_LIMITS_PATH"`
+	// PromptsDir holds *.tmpl files that replace the embedded prompts of the
+	// same name.
+	PromptsDir string `yaml:"promptsDir" toml:"promptsDir" env:"PROMPTS_DIR"`
+	// PIIRedact lists the kinds of personal data replaced with placeholders
+	// before resumes reach a model, or "none".
+	PIIRedact string `yaml:"piiRedact" toml:"piiRedact" env:"PII_REDACT"`
+}
+
+// Auth configures API keys, managed with `server keys`, and the access
+// tokens issued for them. Without a TokenSecret, tokens are signed with a
+// random one and end on restart.
+type Auth struct {
+	Disabled    bool   `yaml:"disabled" toml:"disabled" env:"AUTH_DISABLED"`
+	KeysPath    string `yaml:"keysPath" toml:"keysPath" env:"AUTH_KEYS_PATH"`
+	TokenSecret string `yaml:"tokenSecret" toml:"tokenSecret" env:"AUTH_TOKEN_SECRET" secret:"true"`
+}
+
+// RateLimit holds the per-client limits of LLM routes and of all other
+// routes, and the per-IP limit of all requests before they are
+// authenticated, written as count/unit such as 10/m, or "off".
+type RateLimit struct {
+	LLM  string `yaml:"llm" toml:"llm" env:"RATE_LIMIT_LLM"`
+	Read string `yaml:"read" toml:"read" env:"RATE_LIMIT_READ"`
+	IP   string `yaml:"ip" toml:"ip" env:"RATE_LIMIT_IP"`
+}
+
+// Records keeps the records written on every LLM call, the usage and the
+// redaction audit, in one file per day under Dir. Days older than RetentionDays are deleted, or
+// none when it is 0.
+type Records struct {
+	Dir           string `yaml:"dir" toml:"dir" env:"RECORDS_DIR"`
+	RetentionDays int    `yaml:"retentionDays" toml:"retentionDays" env:"RECORDS_RETENTION_DAYS"`
+}
+
+// Secrets locates the encrypted secrets file and its master keys, read from
+// MasterKeyFile, one per line with the current key first, or else from
+// MasterKey and PreviousMasterKeys.
+type Secrets struct {
+	Path               string   `yaml:"path" toml:"path" env:"SECRETS_PATH"`
+	MasterKey          string   `yaml:"masterKey" toml:"masterKey" env:"SECRETS_MASTER_KEY" secret:"true"`
+	PreviousMasterKeys []string `yaml:"previousMasterKeys" toml:"previousMasterKeys" env:"SECRETS_PREVIOUS_MASTER_KEYS" secret:"true"`
+	MasterKeyFile      string   `yaml:"masterKeyFile" toml:"masterKeyFile" env:"SECRETS_MASTER_KEY_FILE"`
+}
+
+// Reminders sends a follow-up reminder for jobs that stay in one of Statuses
+// for ThresholdDays, checking every Interval.
+type Reminders struct {
+	Enabled       bool     `yaml:"enabled" toml:"enabled" env:"REMINDERS_ENABLED"`
+	Interval      Duration `yaml:"interval" toml:"interval" env:"REMINDER_INTERVAL"`
+	ThresholdDays int      `yaml:"thresholdDays" toml:"thresholdDays" env:"REMINDER_THRESHOLD_DAYS"`
+	Statuses      []string `yaml:"statuses" toml:"statuses" env:"REMINDER_STATUSES"`
+}
+
+// Digest sends the weekly digest on or after Weekday at Hour, server local
+// time.
+type Digest struct {
+	Enabled bool    `yaml:"enabled" toml:"enabled" env:"DIGEST_ENABLED"`
+	Weekday Weekday `yaml:"weekday" toml:"weekday" env:"DIGEST_WEEKDAY"`
+	Hour    int     `yaml:"hour" toml:"hour" env:"DIGEST_HOUR"`
+}
+
+// Notify lists the notifiers of reminders and digests: log, webhook and
+// smtp.
+type Notify struct {
+	Notifiers []string `yaml:"notifiers" toml:"notifiers" env:"NOTIFIERS"`
+	Webhook   Webhook  `yaml:"webhook" toml:"webhook"`
+	SMTP      SMTP     `yaml:"smtp" toml:"smtp"`
+}
+
+// Webhook posts notifications as JSON to URL, with Secret as a bearer token
+// when set.
+type Webhook struct {
+	URL    string `yaml:"url" toml:"url" env:"NOTIFY_WEBHOOK_URL"`
+	Secret string `yaml:"secret" toml:"secret" env:"NOTIFY_WEBHOOK_SECRET" secret:"true"`
+}
+
+type SMTP struct {
+	Host     string   `yaml:"host" toml:"host" env:"SMTP_HOST"`
+	Port     int      `yaml:"port" toml:"port" env:"SMTP_PORT"`
+	Username string   `yaml:"username" toml:"username" env:"SMTP_USERNAME"`
+	Password string   `yaml:"password" toml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string   `yaml:"from" toml:"from" env:"SMTP_FROM"`
+	To       []string `yaml:"to" toml:"to" env:"SMTP_TO"`
+}
+
+// Default returns the configuration used for settings that no source sets.
+func Default() *Config {
+	return &Config{
+		Port:      8000,
+		StorePath: "data/store.json",
+		LLM: LLM{
+			CacheSize:    1000,
+			CacheTTL:     Duration{30 * 24 * time.Hour},
+			BudgetAction: "refuse",
+		},
+		Auth: Auth{
+			KeysPath: "data/api_keys.json",
+		},
+		RateLimit: RateLimit{
+			LLM:  "10/m",
+			Read: "120/m",
+			IP:   "300/m",
+		},
+		Secrets: Secrets{
+			Path: "data/secrets.json",
+		},
+		Records: Records{
+			Dir:           "data/records",
+			RetentionDays: 400,
+		},
+		Reminders: Reminders{
+			Enabled:       true,
+			Interval:      Duration{time.Hour},
+			ThresholdDays: 7,
+			Statuses:      []string{"Applied", "Interview"},
+		},
+		Digest: Digest{
+			Enabled: true,
+			Weekday: Weekday{time.Monday},
+			Hour:    8,
+		},
+		Notify: Notify{
+			Notifiers: []string{"log"},
+			SMTP:      SMTP{Port: 587},
+		},
+	}
+}
+
+// Duration is a time.Duration written as a Go duration such as "30m".
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("%q is not a duration such as 30m", text)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Weekday is a time.Weekday written by name, such as "Monday".
+type Weekday struct {
+	time.Weekday
+}
+
+func (w *Weekday) UnmarshalText(text []byte) error {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), string(text)) {
+			w.Weekday = day
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not a weekday", text)
+}
+
+func (w Weekday) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads the configuration from, in increasing precedence, the defaults,
+// the YAML or TOML file named by the -config flag or CONFIG_FILE, the
+// environment, where empty variables count as unset, and the flags in args.
+// It does not validate the result; see Validate.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+	settings := settingsOf(cfg)
+
+	// Flags are parsed first to find the file, but applied last so they
+	// override it and the environment.
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	path := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML configuration `file` (CONFIG_FILE)")
+	type flagValue struct {
+		setting setting
+		value   string
+	}
+	var flagValues []flagValue
+	for _, s := range settings {
+		if s.secret {
+			continue
+		}
+		usage := "overrides " + s.env
+		set := func(value string) error {
+			flagValues = append(flagValues, flagValue{s, value})
+			return nil
+		}
+		if s.value.Kind() == reflect.Bool {
+			flags.BoolFunc(s.key, usage, set)
+		} else {
+			flags.Func(s.key, usage, set)
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	if *path != "" {
+		if err := readFile(*path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value := os.Getenv(s.env); value != "" {
+			if err := s.set(value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	for _, f := range flagValues {
+		if err := f.setting.set(f.value); err != nil {
+			return nil, fmt.Errorf("invalid -%s: %w", f.setting.key, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// readFile decodes the file at path into cfg, as YAML or TOML by its
+// extension. Unknown keys are errors, so misspelled settings are not
+// silently ignored.
+func readFile(path string, cfg *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+	defer file.Close()
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)
+		err = decoder.Decode(cfg)
+	case ".toml":
+		decoder := toml.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(cfg)
+		// The strict mode error only names the keys in its details.
+		var strict *toml.StrictMissingError
+		if errors.As(err, &strict) {
+			err = errors.New(strict.String())
+		}
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("error decoding config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// setting is one field of a Config, named by its key path in the file,
+// e.g. llm.cacheSize.
+type setting struct {
+	key    string
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+func settingsOf(cfg *Config) []setting {
+	return appendSettings(nil, reflect.ValueOf(cfg).Elem(), "")
+}
+
+// appendSettings walks the fields of section. Fields without an env tag are
+// sections of their own.
+func appendSettings(settings []setting, section reflect.Value, prefix string) []setting {
+	for i := 0; i < section.NumField(); i++ {
+		field := section.Type().Field(i)
+		key := prefix + field.Tag.Get("yaml")
+		env := field.Tag.Get("env")
+		if env == "" {
+			settings = appendSettings(settings, section.Field(i), key+".")
+			continue
+		}
+		settings = append(settings, setting{
+			key:    key,
+			env:    env,
+			secret: field.Tag.Get("secret") == "true",
+			value:  section.Field(i),
+		})
+	}
+	return settings
+}
+
+// set parses text into the setting. Lists are comma separated.
+func (s setting) set(text string) error {
+	if unmarshaler, ok := s.value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(text))
+	}
+
+	switch s.value.Kind() {
+	case reflect.String:
+		s.value.SetString(text)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", text)
+		}
+		s.value.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", text)
+		}
+		s.value.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", text)
+		}
+		s.value.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", s.value.Type())
+	}
+	return nil
+}
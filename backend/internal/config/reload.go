@@ -0,0 +1,103 @@
+package config
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Reloader reloads the configuration from the same sources on SIGHUP. Only
+// settings that are safe to change while the server runs take effect: rate
+// limits, the LLM budget, and which jobs get reminders and when the digest
+// is sent. Changes to anything else are logged and wait for a restart.
+type Reloader interface {
+	// OnReload registers apply to be called with each reloaded
+	// configuration.
+	OnReload(apply func(cfg *Config))
+	// Reload reloads the configuration now. An invalid configuration is
+	// rejected and the current one kept.
+	Reload() error
+	// Watch reloads the configuration whenever the process gets SIGHUP.
+	Watch()
+}
+
+type reloader struct {
+	args []string
+
+	mu      sync.Mutex
+	current *Config
+	hooks   []func(cfg *Config)
+}
+
+// NewReloader creates a reloader for cfg, which was loaded from args.
+func NewReloader(cfg *Config, args []string) Reloader {
+	return &reloader{args: args, current: cfg}
+}
+
+func (r *reloader) OnReload(apply func(cfg *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, apply)
+}
+
+func (r *reloader) Reload() error {
+	next, err := Load(r.args)
+	if err != nil {
+		return err
+	}
+	if err := next.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *r.current
+	updated.RateLimit = next.RateLimit
+	updated.LLM.MonthlyBudget = next.LLM.MonthlyBudget
+	updated.LLM.BudgetAction = next.LLM.BudgetAction
+	updated.Reminders.ThresholdDays = next.Reminders.ThresholdDays
+	updated.Reminders.Statuses = next.Reminders.Statuses
+	updated.Digest.Weekday = next.Digest.Weekday
+	updated.Digest.Hour = next.Digest.Hour
+
+	if pending := changedKeys(&updated, next); len(pending) > 0 {
+		log.Printf("Restart to apply the changes to %s", strings.Join(pending, ", "))
+	}
+
+	r.current = &updated
+	for _, apply := range r.hooks {
+		apply(r.current)
+	}
+	return nil
+}
+
+func (r *reloader) Watch() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			if err := r.Reload(); err != nil {
+				log.Printf("Failed to reload configuration, keeping the current one: %v", err)
+				continue
+			}
+			log.Println("Reloaded configuration")
+		}
+	}()
+}
+
+// changedKeys returns the keys of the settings that differ between a and b.
+func changedKeys(a *Config, b *Config) []string {
+	var keys []string
+	settingsB := settingsOf(b)
+	for i, s := range settingsOf(a) {
+		if !reflect.DeepEqual(s.value.Interface(), settingsB[i].value.Interface()) {
+			keys = append(keys, s.key)
+		}
+	}
+	return keys
+}
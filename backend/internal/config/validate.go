@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/ratelimit"
+	"slices"
+	"strings"
+)
+
+// Validate checks that the server can start with c. It reports every
+// problem at once, each naming the setting's key and environment variable.
+func (c *Config) Validate() error {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if !slices.Contains([]string{"", "debug", "release", "test"}, c.Mode) {
+		problem("mode (MODE) must be debug, release or test, got %q", c.Mode)
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		problem("port (PORT) must be between 1 and 65535, got %d", c.Port)
+	}
+	if c.StorePath == "" {
+		problem("storePath (STORE_PATH) is required")
+	}
+
+	// The server's own credentials are optional when users can bring
+	// theirs, in a header or in a profile, and the keys may be kept in the
+	// secrets file, which needs a master key.
+	if !c.MultiUser() {
+		if c.Notion.APIKey == "" {
+			problem("notion.apiKey (NOTION_API_KEY) is required")
+		}
+		if c.Groq.APIKey == "" {
+			problem("groq.apiKey (GROQ_API_KEY) is required")
+		}
+	}
+	if c.Notion.APIKey != "" && c.Notion.DatabaseID == "" {
+		problem("notion.databaseId (NOTION_DATABASE_ID) is required with notion.apiKey")
+	}
+
+	if c.LLM.CacheSize < 0 {
+		problem("llm.cacheSize (LLM_CACHE_SIZE) must not be negative, got %d", c.LLM.CacheSize)
+	}
+	if c.LLM.CacheTTL.Duration <= 0 {
+		problem("llm.cacheTtl (LLM_CACHE_TTL) must be positive, got %s", c.LLM.CacheTTL)
+	}
+	if c.LLM.MonthlyBudget < 0 {
+		problem("llm.monthlyBudget (LLM_MONTHLY_BUDGET) must not be negative, got %g", c.LLM.MonthlyBudget)
+	}
+	if c.LLM.BudgetAction != "refuse" && c.LLM.BudgetAction != "downgrade" {
+		problem("llm.budgetAction (LLM_BUDGET_ACTION) must be refuse or downgrade, got %q", c.LLM.BudgetAction)
+	}
+
+	if !c.Auth.Disabled && c.Auth.KeysPath == "" {
+		problem("auth.keysPath (AUTH_KEYS_PATH) is required unless auth.disabled is set")
+	}
+
+	if _, err := ratelimit.ParseLimit(c.RateLimit.LLM); err != nil {
+		problem("rateLimit.llm (RATE_LIMIT_LLM): %v", err)
+	}
+	if _, err := ratelimit.ParseLimit(c.RateLimit.Read); err != nil {
+		problem("rateLimit.read (RATE_LIMIT_READ): %v", err)
+	}
+	if _, err := ratelimit.ParseLimit(c.RateLimit.IP); err != nil {
+		problem("rateLimit.ip (RATE_LIMIT_IP): %v", err)
+	}
+
+	if c.Secrets.Path == "" {
+		problem("secrets.path (SECRETS_PATH) is required")
+	}
+
+	if c.Records.Dir == "" {
+		problem("records.dir (RECORDS_DIR) is required")
+	}
+	if c.Records.RetentionDays < 0 {
+		problem("records.retentionDays (RECORDS_RETENTION_DAYS) must not be negative, got %d", c.Records.RetentionDays)
+	}
+
+	if c.Reminders.Interval.Duration <= 0 {
+		problem("reminders.interval (REMINDER_INTERVAL) must be positive, got %s", c.Reminders.Interval)
+	}
+	if c.Reminders.ThresholdDays < 1 {
+		problem("reminders.thresholdDays (REMINDER_THRESHOLD_DAYS) must be at least 1, got %d", c.Reminders.ThresholdDays)
+	}
+	if c.Digest.Hour < 0 || c.Digest.Hour > 23 {
+		problem("digest.hour (DIGEST_HOUR) must be between 0 and 23, got %d", c.Digest.Hour)
+	}
+
+	for _, name := range c.Notify.Notifiers {
+		switch name {
+		case "log":
+		case "webhook":
+			if c.Notify.Webhook.URL == "" {
+				problem("notify.webhook.url (NOTIFY_WEBHOOK_URL) is required by the webhook notifier")
+			}
+		case "smtp":
+			if c.Notify.SMTP.Host == "" || c.Notify.SMTP.From == "" || len(c.Notify.SMTP.To) == 0 {
+				problem("notify.smtp.host, from and to (SMTP_HOST, SMTP_FROM, SMTP_TO) are required by the smtp notifier")
+			}
+		default:
+			problem("notify.notifiers (NOTIFIERS) has unknown notifier %q, expected log, webhook or smtp", name)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration:\n  " + strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// MultiUser reports whether users can bring their own credentials, sealed
+// with CredentialsKey or saved in a profile in the secrets file.
+func (c *Config) MultiUser() bool {
+	return c.CredentialsKey != "" || c.Secrets.MasterKey != "" || c.Secrets.MasterKeyFile != ""
+}
@@ -0,0 +1,58 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractText returns the plain text of a PDF or DOCX file. The format is
+// chosen from the file's magic bytes, falling back to its extension.
+func ExtractText(filename string, data []byte) (string, error) {
+	var text string
+	var err error
+
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		text, err = extractPDF(data)
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")) && strings.EqualFold(filepath.Ext(filename), ".docx"):
+		text, err = extractDOCX(data)
+	default:
+		return "", fmt.Errorf("unsupported document type %q: only PDF and DOCX are supported", filepath.Ext(filename))
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	text = normalize(text)
+	if text == "" {
+		return "", fmt.Errorf("no text found in %s; scanned documents are not supported", filename)
+	}
+
+	return text, nil
+}
+
+// normalize trims trailing spaces and collapses runs of blank lines left by
+// layout-based extraction.
+func normalize(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var out []string
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, line)
+	}
+
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
@@ -0,0 +1,90 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxDocumentXMLSize guards against zip bombs; real resumes are far smaller.
+const maxDocumentXMLSize = 20 << 20
+
+// extractDOCX reads word/document.xml and keeps the text runs, turning
+// paragraphs, breaks and tabs into their plain text equivalents.
+func extractDOCX(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("error opening DOCX: %w", err)
+	}
+
+	var document *zip.File
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			document = file
+			break
+		}
+	}
+	if document == nil {
+		return "", errors.New("error opening DOCX: word/document.xml not found")
+	}
+
+	reader, err := document.Open()
+	if err != nil {
+		return "", fmt.Errorf("error opening DOCX body: %w", err)
+	}
+	defer reader.Close()
+
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxDocumentXMLSize))
+
+	var text strings.Builder
+	inRun := false
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error parsing DOCX body: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			// Tab and break elements also appear in paragraph properties as
+			// tab stops, so only those inside a run produce text.
+			switch element.Name.Local {
+			case "r":
+				inRun = true
+			case "t":
+				inText = true
+			case "tab":
+				if inRun {
+					text.WriteString("\t")
+				}
+			case "br", "cr":
+				if inRun {
+					text.WriteString("\n")
+				}
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "r":
+				inRun = false
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(element)
+			}
+		}
+	}
+
+	return text.String(), nil
+}
@@ -0,0 +1,36 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ledongthuc/pdf"
+)
+
+func extractPDF(data []byte) (text string, err error) {
+	// The PDF parser panics on some malformed files instead of returning an
+	// error, and uploads are untrusted input.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("error reading PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("error opening PDF: %w", err)
+	}
+
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("error extracting PDF text: %w", err)
+	}
+
+	bytes, err := io.ReadAll(plain)
+	if err != nil {
+		return "", fmt.Errorf("error extracting PDF text: %w", err)
+	}
+
+	return string(bytes), nil
+}
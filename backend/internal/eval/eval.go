@@ -0,0 +1,294 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/service"
+	"math"
+	"path"
+	"sort"
+	"time"
+)
+
+// ExtractionFixture is a job posting labeled with the fields SaveJob should
+// extract from it.
+type ExtractionFixture struct {
+	ID       string      `json:"id"`
+	Posting  string      `json:"posting"`
+	Expected ExpectedJob `json:"expected"`
+}
+
+type ExpectedJob struct {
+	Title   string `json:"title"`
+	Company string `json:"company"`
+	Country string `json:"country"`
+}
+
+// ComparisonFixture is a resume and posting pair. ExpectedScore is an
+// optional range a human reviewer considers a reasonable match score.
+type ComparisonFixture struct {
+	ID            string       `json:"id"`
+	Resume        model.Resume `json:"resume"`
+	Posting       string       `json:"posting"`
+	ExpectedScore *ScoreRange  `json:"expectedScore,omitempty"`
+}
+
+type ScoreRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+type Fixtures struct {
+	Extraction []ExtractionFixture
+	Comparison []ComparisonFixture
+}
+
+// LoadFixtures reads extraction/*.json and comparison/*.json from fsys.
+func LoadFixtures(fsys fs.FS) (*Fixtures, error) {
+	var fixtures Fixtures
+	if err := loadDir(fsys, "extraction", func() any { return &ExtractionFixture{} }, func(v any) {
+		fixtures.Extraction = append(fixtures.Extraction, *v.(*ExtractionFixture))
+	}); err != nil {
+		return nil, err
+	}
+	if err := loadDir(fsys, "comparison", func() any { return &ComparisonFixture{} }, func(v any) {
+		fixtures.Comparison = append(fixtures.Comparison, *v.(*ComparisonFixture))
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(fixtures.Extraction) == 0 && len(fixtures.Comparison) == 0 {
+		return nil, fmt.Errorf("no fixtures found")
+	}
+	return &fixtures, nil
+}
+
+func loadDir(fsys fs.FS, dir string, newValue func() any, add func(any)) error {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("error listing %s fixtures: %w", dir, err)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("error reading fixture %s: %w", name, err)
+		}
+		value := newValue()
+		if err := json.Unmarshal(data, value); err != nil {
+			return fmt.Errorf("error decoding fixture %s: %w", name, err)
+		}
+		add(value)
+	}
+	return nil
+}
+
+// Run is the result of evaluating every fixture once. Runs are saved as
+// JSON so later runs can be compared against them.
+type Run struct {
+	StartedAt       string             `json:"startedAt"`
+	Provider        string             `json:"provider"`
+	ExtractionModel string             `json:"extractionModel"`
+	ComparisonModel string             `json:"comparisonModel"`
+	PromptVersions  map[string]string  `json:"promptVersions"`
+	Extraction      []ExtractionResult `json:"extraction"`
+	Comparison      []ComparisonResult `json:"comparison"`
+	Summary         Summary            `json:"summary"`
+}
+
+type ExtractionResult struct {
+	ID      string          `json:"id"`
+	Got     ExpectedJob     `json:"got"`
+	Correct map[string]bool `json:"correct"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// ComparisonResult holds the scores of repeated comparisons of the same
+// inputs. A stable prompt and model give the same score every time.
+type ComparisonResult struct {
+	ID      string   `json:"id"`
+	Scores  []int    `json:"scores"`
+	Mean    float64  `json:"mean"`
+	StdDev  float64  `json:"stdDev"`
+	Spread  int      `json:"spread"`
+	InRange *bool    `json:"inRange,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+type Summary struct {
+	FieldAccuracy      map[string]float64 `json:"fieldAccuracy"`
+	ExtractionAccuracy float64            `json:"extractionAccuracy"`
+	ExtractionErrors   int                `json:"extractionErrors"`
+	MeanStdDev         float64            `json:"meanStdDev"`
+	MaxSpread          int                `json:"maxSpread"`
+	InRangeRate        float64            `json:"inRangeRate"`
+	ComparisonErrors   int                `json:"comparisonErrors"`
+}
+
+// Options select the models under test. Runs is how many times each
+// comparison fixture is scored to measure stability.
+type Options struct {
+	ExtractionModel string
+	ComparisonModel string
+	Runs            int
+}
+
+type Evaluator interface {
+	Run(fixtures *Fixtures) *Run
+}
+
+type evaluator struct {
+	llm     service.LLM
+	options Options
+}
+
+// NewEvaluator creates an evaluator. llm should have no cache, so that every
+// run reaches the model being measured.
+func NewEvaluator(llm service.LLM, options Options) Evaluator {
+	if options.Runs < 1 {
+		options.Runs = 1
+	}
+	return &evaluator{
+		llm:     llm,
+		options: options,
+	}
+}
+
+// Run evaluates every fixture. Failed calls are recorded on the result
+// rather than stopping the run, since they count against the prompt.
+func (e *evaluator) Run(fixtures *Fixtures) *Run {
+	run := &Run{
+		StartedAt:       time.Now().Format(time.RFC3339),
+		Provider:        e.llm.Provider.Name(),
+		ExtractionModel: e.options.ExtractionModel,
+		ComparisonModel: e.options.ComparisonModel,
+		PromptVersions: map[string]string{
+			prompt.ExtractJob.Name():        prompt.ExtractJob.Version(e.llm.Prompts),
+			prompt.CompareJobPosting.Name(): prompt.CompareJobPosting.Version(e.llm.Prompts),
+		},
+	}
+
+	for _, fixture := range fixtures.Extraction {
+		run.Extraction = append(run.Extraction, e.extract(fixture))
+	}
+	for _, fixture := range fixtures.Comparison {
+		run.Comparison = append(run.Comparison, e.compare(fixture))
+	}
+
+	run.Summary = summarize(run)
+	return run
+}
+
+func (e *evaluator) extract(fixture ExtractionFixture) ExtractionResult {
+	result := ExtractionResult{ID: fixture.ID, Correct: map[string]bool{}}
+
+	job, err := service.ExtractJob(context.Background(), e.llm, e.options.ExtractionModel, fixture.Posting, "")
+	if err != nil {
+		result.Error = err.Error()
+		for _, field := range fields {
+			result.Correct[field] = false
+		}
+		return result
+	}
+
+	result.Got = ExpectedJob{Title: job.Title, Company: job.Company, Country: job.Country}
+	result.Correct["title"] = sameText(job.Title, fixture.Expected.Title)
+	result.Correct["company"] = sameCompany(job.Company, fixture.Expected.Company)
+	result.Correct["country"] = sameCountry(job.Country, fixture.Expected.Country)
+	return result
+}
+
+func (e *evaluator) compare(fixture ComparisonFixture) ComparisonResult {
+	result := ComparisonResult{ID: fixture.ID}
+
+	for i := 0; i < e.options.Runs; i++ {
+		comparison, err := service.CompareResume(context.Background(), e.llm, e.options.ComparisonModel, fixture.Resume, fixture.Posting)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Scores = append(result.Scores, comparison.MatchScore)
+	}
+
+	if len(result.Scores) == 0 {
+		return result
+	}
+
+	low, high := result.Scores[0], result.Scores[0]
+	sum := 0.0
+	for _, score := range result.Scores {
+		low, high = min(low, score), max(high, score)
+		sum += float64(score)
+	}
+	result.Mean = sum / float64(len(result.Scores))
+	result.Spread = high - low
+
+	variance := 0.0
+	for _, score := range result.Scores {
+		variance += math.Pow(float64(score)-result.Mean, 2)
+	}
+	result.StdDev = math.Sqrt(variance / float64(len(result.Scores)))
+
+	if fixture.ExpectedScore != nil {
+		inRange := result.Mean >= float64(fixture.ExpectedScore.Min) && result.Mean <= float64(fixture.ExpectedScore.Max)
+		result.InRange = &inRange
+	}
+
+	return result
+}
+
+var fields = []string{"title", "company", "country"}
+
+func summarize(run *Run) Summary {
+	summary := Summary{FieldAccuracy: map[string]float64{}}
+
+	if len(run.Extraction) > 0 {
+		total := 0
+		for _, field := range fields {
+			correct := 0
+			for _, result := range run.Extraction {
+				if result.Correct[field] {
+					correct++
+				}
+			}
+			summary.FieldAccuracy[field] = float64(correct) / float64(len(run.Extraction))
+			total += correct
+		}
+		summary.ExtractionAccuracy = float64(total) / float64(len(run.Extraction)*len(fields))
+	}
+	for _, result := range run.Extraction {
+		if result.Error != "" {
+			summary.ExtractionErrors++
+		}
+	}
+
+	scored, ranged, inRange := 0, 0, 0
+	for _, result := range run.Comparison {
+		summary.ComparisonErrors += len(result.Errors)
+		if len(result.Scores) == 0 {
+			continue
+		}
+		scored++
+		summary.MeanStdDev += result.StdDev
+		summary.MaxSpread = max(summary.MaxSpread, result.Spread)
+		if result.InRange != nil {
+			ranged++
+			if *result.InRange {
+				inRange++
+			}
+		}
+	}
+	if scored > 0 {
+		summary.MeanStdDev /= float64(scored)
+	}
+	if ranged > 0 {
+		summary.InRangeRate = float64(inRange) / float64(ranged)
+	}
+
+	return summary
+}
@@ -0,0 +1,46 @@
+package eval
+
+import (
+	"job-parser-backend/internal/utils"
+	"strings"
+	"unicode"
+)
+
+// countryAliases maps common short forms to the name used in fixtures.
+var countryAliases = map[string]string{
+	"us":                       "united states",
+	"usa":                      "united states",
+	"united states of america": "united states",
+	"uk":                       "united kingdom",
+	"great britain":            "united kingdom",
+	"england":                  "united kingdom",
+	"uae":                      "united arab emirates",
+	"deutschland":              "germany",
+}
+
+// normalize lowercases s and reduces it to words separated by single spaces.
+func normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	}), " ")
+}
+
+func sameText(got string, expected string) bool {
+	return normalize(got) == normalize(expected)
+}
+
+func sameCompany(got string, expected string) bool {
+	return utils.CompanyName(got) == utils.CompanyName(expected)
+}
+
+func sameCountry(got string, expected string) bool {
+	return countryName(got) == countryName(expected)
+}
+
+func countryName(s string) string {
+	name := normalize(s)
+	if alias, ok := countryAliases[name]; ok {
+		return alias
+	}
+	return name
+}
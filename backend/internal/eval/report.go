@@ -0,0 +1,145 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteReport writes a Markdown summary of run. When baseline is not nil the
+// report compares the two runs metric by metric and lists the fixtures whose
+// results changed.
+func WriteReport(w io.Writer, run *Run, baseline *Run) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Evaluation report\n\n")
+	writeRunInfo(&b, "Current", run)
+	if baseline != nil {
+		writeRunInfo(&b, "Baseline", baseline)
+	}
+
+	b.WriteString("\n## Metrics\n\n")
+	if baseline != nil {
+		b.WriteString("| Metric | Baseline | Current | Change |\n|---|---|---|---|\n")
+	} else {
+		b.WriteString("| Metric | Value |\n|---|---|\n")
+	}
+	for _, metric := range metrics {
+		current := metric.value(run.Summary)
+		if baseline == nil {
+			fmt.Fprintf(&b, "| %s | %s |\n", metric.name, metric.format(current))
+			continue
+		}
+		previous := metric.value(baseline.Summary)
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", metric.name, metric.format(previous), metric.format(current), metric.change(current-previous))
+	}
+
+	b.WriteString("\n## Extraction\n\n")
+	previousExtraction := map[string]ExtractionResult{}
+	if baseline != nil {
+		for _, result := range baseline.Extraction {
+			previousExtraction[result.ID] = result
+		}
+	}
+	for _, result := range run.Extraction {
+		fmt.Fprintf(&b, "- **%s**: %s", result.ID, extractionStatus(result))
+		if previous, ok := previousExtraction[result.ID]; ok {
+			if before, after := extractionStatus(previous), extractionStatus(result); before != after {
+				fmt.Fprintf(&b, " (was %s)", before)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Comparison\n\n")
+	previousComparison := map[string]ComparisonResult{}
+	if baseline != nil {
+		for _, result := range baseline.Comparison {
+			previousComparison[result.ID] = result
+		}
+	}
+	for _, result := range run.Comparison {
+		fmt.Fprintf(&b, "- **%s**: scores %v, mean %.1f, spread %d", result.ID, result.Scores, result.Mean, result.Spread)
+		if result.InRange != nil && !*result.InRange {
+			b.WriteString(", outside expected range")
+		}
+		if len(result.Errors) > 0 {
+			fmt.Fprintf(&b, ", %d errors", len(result.Errors))
+		}
+		if previous, ok := previousComparison[result.ID]; ok && len(previous.Scores) > 0 {
+			fmt.Fprintf(&b, " (was mean %.1f, spread %d)", previous.Mean, previous.Spread)
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeRunInfo(b *strings.Builder, label string, run *Run) {
+	versions := make([]string, 0, len(run.PromptVersions))
+	for _, version := range run.PromptVersions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	fmt.Fprintf(b, "- %s: %s via %s, extraction model %s, comparison model %s, prompts %s\n",
+		label, run.StartedAt, run.Provider, run.ExtractionModel, run.ComparisonModel, strings.Join(versions, ", "))
+}
+
+type metric struct {
+	name           string
+	value          func(Summary) float64
+	percent        bool
+	higherIsBetter bool
+}
+
+var metrics = []metric{
+	{name: "Title accuracy", value: func(s Summary) float64 { return s.FieldAccuracy["title"] }, percent: true, higherIsBetter: true},
+	{name: "Company accuracy", value: func(s Summary) float64 { return s.FieldAccuracy["company"] }, percent: true, higherIsBetter: true},
+	{name: "Country accuracy", value: func(s Summary) float64 { return s.FieldAccuracy["country"] }, percent: true, higherIsBetter: true},
+	{name: "Extraction accuracy", value: func(s Summary) float64 { return s.ExtractionAccuracy }, percent: true, higherIsBetter: true},
+	{name: "Extraction errors", value: func(s Summary) float64 { return float64(s.ExtractionErrors) }},
+	{name: "Mean score std dev", value: func(s Summary) float64 { return s.MeanStdDev }},
+	{name: "Max score spread", value: func(s Summary) float64 { return float64(s.MaxSpread) }},
+	{name: "Scores in expected range", value: func(s Summary) float64 { return s.InRangeRate }, percent: true, higherIsBetter: true},
+	{name: "Comparison errors", value: func(s Summary) float64 { return float64(s.ComparisonErrors) }},
+}
+
+func (m metric) format(value float64) string {
+	if m.percent {
+		return fmt.Sprintf("%.0f%%", value*100)
+	}
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", value), "0"), ".")
+}
+
+func (m metric) change(delta float64) string {
+	if delta == 0 {
+		return "="
+	}
+	verdict := "worse"
+	if delta > 0 == m.higherIsBetter {
+		verdict = "better"
+	}
+	if m.percent {
+		return fmt.Sprintf("%+.0f pts (%s)", delta*100, verdict)
+	}
+	return fmt.Sprintf("%+.2f (%s)", delta, verdict)
+}
+
+func extractionStatus(result ExtractionResult) string {
+	if result.Error != "" {
+		return "error"
+	}
+	var wrong []string
+	for _, field := range fields {
+		if !result.Correct[field] {
+			wrong = append(wrong, field)
+		}
+	}
+	if len(wrong) == 0 {
+		return "all fields correct"
+	}
+	return "wrong " + strings.Join(wrong, ", ")
+}
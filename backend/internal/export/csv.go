@@ -0,0 +1,35 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"job-parser-backend/internal/model"
+)
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (Writer, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, fmt.Errorf("error writing CSV header: %w", err)
+	}
+	return &csvWriter{writer: writer}, nil
+}
+
+func (c *csvWriter) Write(job model.Job) error {
+	if err := c.writer.Write(row(job)); err != nil {
+		return fmt.Errorf("error writing CSV row: %w", err)
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	if err := c.writer.Error(); err != nil {
+		return fmt.Errorf("error flushing CSV: %w", err)
+	}
+	return nil
+}
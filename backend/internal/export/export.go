@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"job-parser-backend/internal/model"
+	"strings"
+)
+
+// Writer streams jobs to an export file one row at a time. Close must be
+// called to flush buffered rows and write any trailer the format needs.
+type Writer interface {
+	Write(job model.Job) error
+	Close() error
+}
+
+const (
+	FormatCSV   string = "csv"
+	FormatJSONL string = "jsonl"
+	FormatXLSX  string = "xlsx"
+)
+
+// columns is the row layout shared by the tabular formats.
+var columns = []string{
+	"ID",
+	"Title",
+	"Company",
+	"Country",
+	"Status",
+	"URL",
+	"Created Date",
+	"Applied Date",
+	"Status History",
+	"Description",
+}
+
+// NewWriter returns a Writer for format that writes to w.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ContentType returns the MIME type and file extension for format.
+func ContentType(format string) (string, string, error) {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8", ".csv", nil
+	case FormatJSONL:
+		return "application/x-ndjson", ".jsonl", nil
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ".xlsx", nil
+	default:
+		return "", "", fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func row(job model.Job) []string {
+	return []string{
+		job.ID,
+		job.Title,
+		job.Company,
+		job.Country,
+		job.Status,
+		job.URL,
+		job.CreatedDate,
+		job.AppliedDate,
+		formatStatusHistory(job.StatusHistory),
+		job.Description,
+	}
+}
+
+// formatStatusHistory flattens the history into a single cell, e.g.
+// "2025-05-01T10:00:00Z Applied; 2025-05-09T16:30:00Z Interview".
+func formatStatusHistory(history []model.StatusChange) string {
+	entries := make([]string, 0, len(history))
+	for _, change := range history {
+		entries = append(entries, change.ChangedAt+" "+change.Status)
+	}
+	return strings.Join(entries, "; ")
+}
@@ -0,0 +1,29 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"job-parser-backend/internal/model"
+)
+
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) Writer {
+	return &jsonlWriter{encoder: json.NewEncoder(w)}
+}
+
+// Write emits one JSON object per line; json.Encoder terminates each value
+// with a newline.
+func (j *jsonlWriter) Write(job model.Job) error {
+	if err := j.encoder.Encode(job); err != nil {
+		return fmt.Errorf("error writing JSONL row: %w", err)
+	}
+	return nil
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
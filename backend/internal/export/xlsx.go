@@ -0,0 +1,143 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"job-parser-backend/internal/model"
+	"strconv"
+	"unicode/utf8"
+)
+
+// maxCellLength is the largest number of characters Excel accepts in a cell.
+const maxCellLength = 32767
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+const rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const workbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Jobs" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+const workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+const sheetHeaderXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetFooterXML = `</sheetData></worksheet>`
+
+// xlsxWriter produces a minimal Office Open XML workbook with a single sheet.
+// The fixed package parts are written up front and the sheet is streamed last,
+// using inline strings so no shared string table has to be held in memory.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+}
+
+func newXLSXWriter(w io.Writer) (Writer, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", workbookXML},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+	for _, part := range parts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, fmt.Errorf("error writing %s: %w", part.name, err)
+		}
+	}
+
+	entry, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("error creating worksheet: %w", err)
+	}
+
+	writer := &xlsxWriter{archive: archive, sheet: bufio.NewWriter(entry)}
+	if _, err := writer.sheet.WriteString(sheetHeaderXML); err != nil {
+		return nil, fmt.Errorf("error writing worksheet: %w", err)
+	}
+	if err := writer.writeRow(columns); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (x *xlsxWriter) Write(job model.Job) error {
+	return x.writeRow(row(job))
+}
+
+func (x *xlsxWriter) writeRow(cells []string) error {
+	x.rows++
+	rowNumber := strconv.Itoa(x.rows)
+
+	x.sheet.WriteString(`<row r="` + rowNumber + `">`)
+	for i, value := range cells {
+		x.sheet.WriteString(`<c r="` + columnName(i) + rowNumber + `" t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(x.sheet, []byte(truncate(value, maxCellLength))); err != nil {
+			return fmt.Errorf("error writing worksheet cell: %w", err)
+		}
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	if _, err := x.sheet.WriteString(`</row>`); err != nil {
+		return fmt.Errorf("error writing worksheet row: %w", err)
+	}
+
+	return nil
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := x.sheet.WriteString(sheetFooterXML); err != nil {
+		return fmt.Errorf("error writing worksheet: %w", err)
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return fmt.Errorf("error flushing worksheet: %w", err)
+	}
+	if err := x.archive.Close(); err != nil {
+		return fmt.Errorf("error closing workbook: %w", err)
+	}
+	return nil
+}
+
+// columnName converts a zero-based column index to its spreadsheet letters.
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func truncate(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	return string([]rune(value)[:limit])
+}
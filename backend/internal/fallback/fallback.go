@@ -0,0 +1,117 @@
+package fallback
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"job-parser-backend/internal/client"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:embed chains.json
+var defaultConfig []byte
+
+// Config is the file format of the fallback chains. Each task, named after
+// its prompt, lists "provider/model" steps in the order they are tried.
+// Timeout bounds each attempt, so a hung model falls through to the next.
+type Config struct {
+	Timeout string              `json:"timeout"`
+	Tasks   map[string][]string `json:"tasks"`
+}
+
+// Step is one model of a chain and the provider that serves it.
+type Step struct {
+	Provider client.LLMProvider
+	Model    string
+}
+
+func (s Step) String() string {
+	return s.Provider.Name() + "/" + s.Model
+}
+
+// Chains are the resolved fallback chains. A nil *Chains has no chains and
+// no timeout.
+type Chains struct {
+	timeout time.Duration
+	tasks   map[string][]Step
+}
+
+// LoadConfig reads a chains file, or the embedded default when path is
+// empty.
+func LoadConfig(path string) (*Config, error) {
+	data := defaultConfig
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading fallback chains: %w", err)
+		}
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error decoding fallback chains: %w", err)
+	}
+
+	return &config, nil
+}
+
+// NewChains resolves the steps of config against providers, keyed by name.
+// Every provider a chain names must be configured, and tasks must be one of
+// tasks, so a typo fails at startup rather than silently using the default
+// model.
+func NewChains(config *Config, providers map[string]client.LLMProvider, tasks ...string) (*Chains, error) {
+	chains := &Chains{tasks: make(map[string][]Step, len(config.Tasks))}
+
+	if config.Timeout != "" {
+		timeout, err := time.ParseDuration(config.Timeout)
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid fallback timeout %q", config.Timeout)
+		}
+		chains.timeout = timeout
+	}
+
+	for task, names := range config.Tasks {
+		if !slices.Contains(tasks, task) {
+			return nil, fmt.Errorf("fallback chain for unknown task %q, expected one of %s", task, strings.Join(tasks, ", "))
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("fallback chain for %s is empty", task)
+		}
+
+		steps := make([]Step, 0, len(names))
+		for _, name := range names {
+			providerName, modelName, ok := strings.Cut(name, "/")
+			if !ok || providerName == "" || modelName == "" {
+				return nil, fmt.Errorf("invalid fallback step %q for %s, expected provider/model", name, task)
+			}
+			provider, ok := providers[providerName]
+			if !ok {
+				return nil, fmt.Errorf("fallback step %q for %s uses provider %s, which is not configured", name, task, providerName)
+			}
+			steps = append(steps, Step{Provider: provider, Model: modelName})
+		}
+		chains.tasks[task] = steps
+	}
+
+	return chains, nil
+}
+
+// For returns the chain of task, or nil when it has none.
+func (c *Chains) For(task string) []Step {
+	if c == nil {
+		return nil
+	}
+	return c.tasks[task]
+}
+
+// Timeout bounds each attempt, or is 0 for no bound.
+func (c *Chains) Timeout() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return c.timeout
+}
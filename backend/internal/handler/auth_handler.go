@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"job-parser-backend/internal/auth"
+	"job-parser-backend/internal/tenant"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// calendarPath may take its token from the query string, because calendar
+// apps subscribe by URL and cannot send headers. A URL is easily shared, so
+// such a token must have only the read scope, and Logger leaves it out of
+// the access log.
+const calendarPath = "/api/calendar.ics"
+
+// routeScopes lists the routes that need a scope other than the default,
+// which is read for GET and write for everything else.
+var routeScopes = map[string]string{
+	"POST /api/job/compare":                    auth.ScopeCompare,
+	"POST /api/resume/parse":                   auth.ScopeCompare,
+	"POST /api/job/:pageID/cover-letter":       auth.ScopeCompare,
+	"POST /api/job/:pageID/interview-prep":     auth.ScopeCompare,
+	"POST /api/job/:pageID/resume-suggestions": auth.ScopeCompare,
+	"GET /api/job/ranked":                      auth.ScopeCompare,
+	"GET /api/llm/cache":                       auth.ScopeAdmin,
+	"POST /api/auth/token":                     "",
+}
+
+type AuthHandler interface {
+	issueTokenHandler(context *gin.Context)
+	registerAuthHandler(router *gin.Engine)
+}
+
+type authHandler struct {
+	authenticator auth.Authenticator
+}
+
+func CreateAuthHandler(authenticator auth.Authenticator, router *gin.Engine) AuthHandler {
+	authHandler := &authHandler{authenticator: authenticator}
+	authHandler.registerAuthHandler(router)
+	return authHandler
+}
+
+func (h *authHandler) registerAuthHandler(router *gin.Engine) {
+	router.POST("/api/auth/token", h.issueTokenHandler)
+}
+
+// issueTokenHandler exchanges the API key of the request for a short-lived
+// access token, optionally with fewer scopes and a shorter ttl (a Go
+// duration, 15m by default and at most 1h).
+func (h *authHandler) issueTokenHandler(context *gin.Context) {
+	principal := auth.FromContext(context.Request.Context())
+	if principal.Token {
+		context.JSON(http.StatusForbidden, gin.H{"error": "Tokens are issued for API keys, not other tokens"})
+		return
+	}
+
+	var request struct {
+		Scopes []string `json:"scopes"`
+		TTL    string   `json:"ttl"`
+	}
+	if context.Request.ContentLength != 0 {
+		if err := context.ShouldBindJSON(&request); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+
+	var ttl time.Duration
+	if request.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(request.TTL)
+		if err != nil || ttl <= 0 {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ttl, expected a duration such as 15m"})
+			return
+		}
+	}
+
+	token, err := h.authenticator.IssueToken(principal, request.Scopes, ttl)
+	if errors.Is(err, auth.ErrScopeNotGranted) {
+		context.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue token"})
+		return
+	}
+
+	context.JSON(http.StatusOK, token)
+}
+
+// Authenticate is middleware that requires an API key or access token on
+// every /api route, sent as "Authorization: Bearer <key or token>" or in
+// X-API-Key, and checks the route's scope. The calendar feed also takes a
+// read-only key as ?token=. It must be added before the routes.
+func Authenticate(authenticator auth.Authenticator) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		path := context.Request.URL.Path
+		if !strings.HasPrefix(path, "/api/") {
+			context.Next()
+			return
+		}
+
+		key, inURL := credential(context)
+		principal, err := authenticator.Authenticate(key)
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			context.Header("WWW-Authenticate", `Bearer realm="api"`)
+			context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			logError(err)
+			context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate request"})
+			return
+		}
+
+		if inURL && !readOnly(principal) {
+			context.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Calendar URLs need a key with only the read scope"})
+			return
+		}
+		if scope := requiredScope(context.Request.Method, context.FullPath()); scope != "" && !principal.Allows(scope) {
+			context.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This route needs the " + scope + " scope"})
+			return
+		}
+
+		ctx := auth.WithPrincipal(context.Request.Context(), principal)
+		if principal.User != "" {
+			ctx = tenant.WithUser(ctx, principal.User)
+		}
+		context.Request = context.Request.WithContext(ctx)
+		context.Next()
+	}
+}
+
+// credential returns the key or token of the request, and whether it was
+// taken from the URL.
+func credential(context *gin.Context) (string, bool) {
+	if value, ok := strings.CutPrefix(context.GetHeader("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(value), false
+	}
+	if value := context.GetHeader("X-API-Key"); value != "" {
+		return value, false
+	}
+	if context.Request.URL.Path == calendarPath {
+		if value := context.Query("token"); value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// readOnly reports whether principal has the read scope and no other.
+func readOnly(principal *auth.Principal) bool {
+	for _, scope := range principal.Scopes {
+		if scope != auth.ScopeRead {
+			return false
+		}
+	}
+	return len(principal.Scopes) > 0
+}
+
+func requiredScope(method string, route string) string {
+	if scope, ok := routeScopes[method+" "+route]; ok {
+		return scope
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return auth.ScopeRead
+	}
+	return auth.ScopeWrite
+}
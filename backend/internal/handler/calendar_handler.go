@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"bytes"
+	"job-parser-backend/internal/calendar"
+	"job-parser-backend/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarHandler interface {
+	getCalendarHandler(context *gin.Context)
+	registerCalendarHandler(router *gin.Engine)
+}
+
+type calendarHandler struct {
+	service service.JobService
+}
+
+func CreateCalendarHandler(svc service.JobService, router *gin.Engine) CalendarHandler {
+	calendarHandler := &calendarHandler{service: svc}
+	calendarHandler.registerCalendarHandler(router)
+	return calendarHandler
+}
+
+func (h *calendarHandler) registerCalendarHandler(router *gin.Engine) {
+	router.GET("/api/calendar.ics", h.getCalendarHandler)
+}
+
+func (h *calendarHandler) getCalendarHandler(context *gin.Context) {
+	jobs, err := h.service.GetJobEvents(context.Request.Context())
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job events"})
+		return
+	}
+
+	var buffer bytes.Buffer
+	if err := calendar.WriteICS(&buffer, jobs, time.Now()); err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build calendar"})
+		return
+	}
+
+	context.Header("Content-Disposition", `inline; filename="jobs.ics"`)
+	context.Data(http.StatusOK, "text/calendar; charset=utf-8", buffer.Bytes())
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/service"
+	"job-parser-backend/internal/usage"
+	"job-parser-backend/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CoverLetterHandler interface {
+	generateCoverLetterHandler(context *gin.Context)
+	getCoverLettersHandler(context *gin.Context)
+	getCoverLetterHandler(context *gin.Context)
+	getSettingsHandler(context *gin.Context)
+	updateSettingsHandler(context *gin.Context)
+	registerCoverLetterHandler(router *gin.Engine)
+}
+
+type coverLetterHandler struct {
+	service service.CoverLetterService
+}
+
+func CreateCoverLetterHandler(svc service.CoverLetterService, router *gin.Engine) CoverLetterHandler {
+	coverLetterHandler := &coverLetterHandler{service: svc}
+	coverLetterHandler.registerCoverLetterHandler(router)
+	return coverLetterHandler
+}
+
+func (h *coverLetterHandler) registerCoverLetterHandler(router *gin.Engine) {
+	router.GET("/api/job/:pageID/cover-letter", h.getCoverLetterHandler)
+	router.GET("/api/job/:pageID/cover-letters", h.getCoverLettersHandler)
+	router.GET("/api/settings/cover-letter", h.getSettingsHandler)
+
+	router.POST("/api/job/:pageID/cover-letter", h.generateCoverLetterHandler)
+
+	router.PUT("/api/settings/cover-letter", h.updateSettingsHandler)
+}
+
+func (h *coverLetterHandler) generateCoverLetterHandler(context *gin.Context) {
+	var req model.CoverLetterRequest
+	if err := context.ShouldBindJSON(&req); err != nil && context.Request.ContentLength > 0 {
+		logError(err)
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	letter, err := h.service.GenerateCoverLetter(context.Request.Context(), context.Param("pageID"), req)
+	switch {
+	case errors.Is(err, service.ErrInvalidLength), errors.Is(err, service.ErrNoJobPosting):
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrResumeNotFound), errors.Is(err, service.ErrNoResume):
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, usage.ErrBudgetExceeded):
+		context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate cover letter"})
+		return
+	}
+	context.JSON(http.StatusCreated, letter)
+}
+
+func (h *coverLetterHandler) getCoverLettersHandler(context *gin.Context) {
+	letters, err := h.service.GetCoverLetters(context.Request.Context(), context.Param("pageID"))
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cover letters"})
+		return
+	}
+	context.JSON(http.StatusOK, letters)
+}
+
+// getCoverLetterHandler returns one draft, the latest unless ?version= is
+// given. ?format=markdown or ?format=text downloads it as a file instead of
+// JSON.
+func (h *coverLetterHandler) getCoverLetterHandler(context *gin.Context) {
+	version := 0
+	if value := context.Query("version"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cover letter version"})
+			return
+		}
+		version = parsed
+	}
+
+	format := context.DefaultQuery("format", "json")
+	if format != "json" && format != "markdown" && format != "text" {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported cover letter format"})
+		return
+	}
+
+	letter, err := h.service.GetCoverLetter(context.Request.Context(), context.Param("pageID"), version)
+	if errors.Is(err, service.ErrCoverLetterNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"error": "Cover letter not found"})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cover letter"})
+		return
+	}
+
+	switch format {
+	case "markdown":
+		context.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="cover-letter-v%d.md"`, letter.Version))
+		context.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(letter.Content))
+	case "text":
+		context.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="cover-letter-v%d.txt"`, letter.Version))
+		context.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(utils.MarkdownToText(letter.Content)))
+	default:
+		context.JSON(http.StatusOK, letter)
+	}
+}
+
+func (h *coverLetterHandler) getSettingsHandler(context *gin.Context) {
+	settings, err := h.service.GetSettings(context.Request.Context())
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cover letter settings"})
+		return
+	}
+	context.JSON(http.StatusOK, settings)
+}
+
+func (h *coverLetterHandler) updateSettingsHandler(context *gin.Context) {
+	var req model.CoverLetterSettings
+	if err := context.BindJSON(&req); err != nil {
+		logError(err)
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(context.Request.Context(), req)
+	if errors.Is(err, service.ErrInvalidLength) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cover letter settings"})
+		return
+	}
+	context.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"job-parser-backend/internal/tenant"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// credentialsHeader carries a token from POST /api/credentials, so the
+// request runs with the Notion and Groq keys sealed in it.
+const credentialsHeader = "X-Credentials"
+
+const credentialsPath = "/api/credentials"
+
+type CredentialsHandler interface {
+	sealCredentialsHandler(context *gin.Context)
+	saveProfileCredentialsHandler(context *gin.Context)
+	registerCredentialsHandler(router *gin.Engine)
+}
+
+type credentialsHandler struct {
+	resolver tenant.Resolver
+}
+
+func CreateCredentialsHandler(resolver tenant.Resolver, router *gin.Engine) CredentialsHandler {
+	credentialsHandler := &credentialsHandler{resolver: resolver}
+	credentialsHandler.registerCredentialsHandler(router)
+	return credentialsHandler
+}
+
+func (h *credentialsHandler) registerCredentialsHandler(router *gin.Engine) {
+	router.POST(credentialsPath, h.sealCredentialsHandler)
+	router.PUT("/api/profile/credentials", h.saveProfileCredentialsHandler)
+}
+
+// sealCredentialsHandler encrypts the posted keys into a token for the
+// X-Credentials header. The server keeps nothing.
+func (h *credentialsHandler) sealCredentialsHandler(context *gin.Context) {
+	var credentials tenant.Credentials
+	if err := context.ShouldBindJSON(&credentials); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	token, err := h.resolver.Seal(credentials)
+	if errors.Is(err, tenant.ErrMultiUserDisabled) {
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, tenant.ErrInvalidCredentials) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to seal credentials"})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+// saveProfileCredentialsHandler stores the posted keys, encrypted, on the
+// authenticated user's profile, so their requests need no header.
+func (h *credentialsHandler) saveProfileCredentialsHandler(context *gin.Context) {
+	var credentials tenant.Credentials
+	if err := context.ShouldBindJSON(&credentials); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err := h.resolver.SaveProfile(context.Request.Context(), credentials)
+	if errors.Is(err, tenant.ErrNoCredentials) {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": "Profiles require an authenticated user"})
+		return
+	}
+	if errors.Is(err, tenant.ErrMultiUserDisabled) {
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, tenant.ErrInvalidCredentials) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save credentials"})
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+// Credentials is middleware that resolves the Notion and Groq credentials
+// of each request, from the X-Credentials header or the authenticated
+// user's profile. Requests without any use the server's own, and are
+// refused when it has none. It must be added after authentication and
+// before the routes.
+func Credentials(resolver tenant.Resolver) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		ctx := context.Request.Context()
+		credentials, ok, err := resolver.Resolve(ctx, context.GetHeader(credentialsHeader))
+		switch {
+		case errors.Is(err, tenant.ErrMultiUserDisabled):
+			context.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, tenant.ErrInvalidToken):
+			context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			logError(err)
+			context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve credentials"})
+			return
+		}
+
+		if ok {
+			context.Request = context.Request.WithContext(tenant.WithCredentials(ctx, credentials))
+		} else if !resolver.HasDefault() && needsCredentials(context.Request.URL.Path) {
+			context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Credentials required, send an X-Credentials token or save a profile"})
+			return
+		}
+		context.Next()
+	}
+}
+
+// needsCredentials reports whether path may call Notion or a model. The
+// credential endpoints themselves and the health check do not.
+func needsCredentials(path string) bool {
+	return strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, credentialsPath) && path != "/api/profile/credentials"
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/service"
+	"job-parser-backend/internal/usage"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InterviewPrepHandler interface {
+	generateInterviewPrepHandler(context *gin.Context)
+	getInterviewPrepHandler(context *gin.Context)
+	registerInterviewPrepHandler(router *gin.Engine)
+}
+
+type interviewPrepHandler struct {
+	service service.InterviewPrepService
+}
+
+func CreateInterviewPrepHandler(svc service.InterviewPrepService, router *gin.Engine) InterviewPrepHandler {
+	interviewPrepHandler := &interviewPrepHandler{service: svc}
+	interviewPrepHandler.registerInterviewPrepHandler(router)
+	return interviewPrepHandler
+}
+
+func (h *interviewPrepHandler) registerInterviewPrepHandler(router *gin.Engine) {
+	router.GET("/api/job/:pageID/interview-prep", h.getInterviewPrepHandler)
+
+	router.POST("/api/job/:pageID/interview-prep", h.generateInterviewPrepHandler)
+}
+
+func (h *interviewPrepHandler) generateInterviewPrepHandler(context *gin.Context) {
+	var req model.CompareRequest
+	if err := context.ShouldBindJSON(&req); err != nil && context.Request.ContentLength > 0 {
+		logError(err)
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	prep, err := h.service.GenerateInterviewPrep(context.Request.Context(), context.Param("pageID"), req)
+	switch {
+	case errors.Is(err, service.ErrNoJobPosting):
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrResumeNotFound), errors.Is(err, service.ErrNoResume):
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, usage.ErrBudgetExceeded):
+		context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate interview prep"})
+		return
+	}
+	context.JSON(http.StatusCreated, prep)
+}
+
+func (h *interviewPrepHandler) getInterviewPrepHandler(context *gin.Context) {
+	prep, err := h.service.GetInterviewPrep(context.Request.Context(), context.Param("pageID"))
+	if errors.Is(err, service.ErrInterviewPrepNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"error": "Interview prep not found"})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch interview prep"})
+		return
+	}
+	context.JSON(http.StatusOK, prep)
+}
@@ -1,10 +1,16 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/export"
 	"job-parser-backend/internal/model"
 	"job-parser-backend/internal/service"
+	"job-parser-backend/internal/usage"
 	"log"
 	"net/http"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,6 +21,8 @@ type JobHandler interface {
 	updateJobHandler(context *gin.Context)
 	getStatsHandler(context *gin.Context)
 	getStreakHandler(context *gin.Context)
+	exportJobsHandler(context *gin.Context)
+	getJobHandler(context *gin.Context)
 	registerJobHandler(router *gin.Engine)
 }
 
@@ -32,6 +40,8 @@ func (h *jobHandler) registerJobHandler(router *gin.Engine) {
 	router.GET("/api/job/recent", h.getRecentlySavedJobsHandler)
 	router.GET("/api/job/stats", h.getStatsHandler)
 	router.GET("/api/job/streak", h.getStreakHandler)
+	router.GET("/api/job/export", h.exportJobsHandler)
+	router.GET("/api/job/:pageID", h.getJobHandler)
 
 	router.POST("/api/job", h.saveJobHandler)
 	router.POST("/api/job/compare", h.compareJobPostingHandler)
@@ -47,7 +57,11 @@ func (h *jobHandler) saveJobHandler(context *gin.Context) {
 		return
 	}
 
-	res, err := h.service.SaveJob(req)
+	res, err := h.service.SaveJob(context.Request.Context(), req)
+	if errors.Is(err, usage.ErrBudgetExceeded) {
+		context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		logError(err)
 		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save job"})
@@ -57,18 +71,26 @@ func (h *jobHandler) saveJobHandler(context *gin.Context) {
 }
 
 func (h *jobHandler) compareJobPostingHandler(context *gin.Context) {
-	type CompareJobPostingRequest struct {
-		Resume     any    `json:"resume"`
-		JobPosting string `json:"jobPosting"`
-	}
-	var req CompareJobPostingRequest
+	var req model.CompareRequest
 	if err := context.BindJSON(&req); err != nil {
 		logError(err)
 		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
-	res, err := h.service.CompareJobPosting(req.Resume, req.JobPosting)
+	res, err := h.service.CompareJobPosting(context.Request.Context(), req)
+	if errors.Is(err, service.ErrResumeNotFound) || errors.Is(err, service.ErrNoResume) {
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrNoJobPosting) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, usage.ErrBudgetExceeded) {
+		context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		logError(err)
 		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare job posting"})
@@ -77,9 +99,23 @@ func (h *jobHandler) compareJobPostingHandler(context *gin.Context) {
 	context.JSON(http.StatusOK, res)
 }
 
+func (h *jobHandler) getJobHandler(context *gin.Context) {
+	job, err := h.service.GetJob(context.Request.Context(), context.Param("pageID"))
+	if errors.Is(err, service.ErrJobNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job"})
+		return
+	}
+	context.JSON(http.StatusOK, job)
+}
+
 func (h *jobHandler) getRecentlySavedJobsHandler(context *gin.Context) {
 	status := context.Query("status")
-	jobs, err := h.service.GetRecentlySavedJobs(status)
+	jobs, err := h.service.GetRecentlySavedJobs(context.Request.Context(), status)
 	if err != nil {
 		logError(err)
 		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch saved jobs"})
@@ -102,7 +138,11 @@ func (h *jobHandler) updateJobHandler(context *gin.Context) {
 		return
 	}
 
-	err := h.service.UpdateJob(pageID, req)
+	err := h.service.UpdateJob(context.Request.Context(), pageID, req)
+	if errors.Is(err, service.ErrNothingToUpdate) || errors.Is(err, service.ErrUnknownEventType) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		logError(err)
 		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job"})
@@ -114,7 +154,7 @@ func (h *jobHandler) updateJobHandler(context *gin.Context) {
 func (h *jobHandler) getStatsHandler(context *gin.Context) {
 	rangeParam := context.Query("range")
 
-	statResult, err := h.service.GetStats(rangeParam)
+	statResult, err := h.service.GetStats(context.Request.Context(), rangeParam)
 	if err != nil {
 		logError(err)
 		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
@@ -124,7 +164,7 @@ func (h *jobHandler) getStatsHandler(context *gin.Context) {
 }
 
 func (h *jobHandler) getStreakHandler(context *gin.Context) {
-	streakStat, err := h.service.GetStreak()
+	streakStat, err := h.service.GetStreak(context.Request.Context())
 	if err != nil {
 		logError(err)
 		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch streak"})
@@ -133,6 +173,48 @@ func (h *jobHandler) getStreakHandler(context *gin.Context) {
 	context.JSON(http.StatusOK, streakStat)
 }
 
+func (h *jobHandler) exportJobsHandler(context *gin.Context) {
+	format := context.DefaultQuery("format", export.FormatCSV)
+	status := context.Query("status")
+
+	contentType, extension, err := export.ContentType(format)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported export format"})
+		return
+	}
+
+	filename := fmt.Sprintf("jobs-%s%s", time.Now().Format("20060102"), extension)
+	context.Header("Content-Type", contentType)
+	context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	context.Status(http.StatusOK)
+
+	writer, err := export.NewWriter(format, context.Writer)
+	if err != nil {
+		logError(err)
+		context.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	// Headers are already sent once rows start streaming, so a failure part
+	// way through can only be logged and the response cut short.
+	err = h.service.ExportJobs(context.Request.Context(), status, func(job model.Job) error {
+		if err := writer.Write(job); err != nil {
+			return err
+		}
+		context.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		logError(err)
+		context.Abort()
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		logError(err)
+	}
+}
+
 func logError(err error) {
 	log.Printf("Error: %v", err)
 }
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"job-parser-backend/internal/llmcache"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// llmCacheHeader set to "bypass" makes a request skip cached LLM replies and
+// refresh them.
+const llmCacheHeader = "X-LLM-Cache"
+
+type LLMCacheHandler interface {
+	getLLMCacheStatsHandler(context *gin.Context)
+	registerLLMCacheHandler(router *gin.Engine)
+}
+
+type llmCacheHandler struct {
+	cache llmcache.Cache
+}
+
+func CreateLLMCacheHandler(cache llmcache.Cache, router *gin.Engine) LLMCacheHandler {
+	llmCacheHandler := &llmCacheHandler{cache: cache}
+	llmCacheHandler.registerLLMCacheHandler(router)
+	return llmCacheHandler
+}
+
+func (h *llmCacheHandler) registerLLMCacheHandler(router *gin.Engine) {
+	router.GET("/api/llm/cache", h.getLLMCacheStatsHandler)
+}
+
+func (h *llmCacheHandler) getLLMCacheStatsHandler(context *gin.Context) {
+	context.JSON(http.StatusOK, h.cache.Stats())
+}
+
+// LLMCacheBypass is middleware that marks the request context when the
+// client asks to bypass the LLM cache. It must be added before the routes.
+func LLMCacheBypass() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if context.GetHeader(llmCacheHeader) == "bypass" {
+			context.Request = context.Request.WithContext(llmcache.WithBypass(context.Request.Context()))
+		}
+		context.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger is gin's request logger, in gin's format, with the token of
+// calendar URLs left out, so the key in them does not end up in the logs.
+func Logger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(params gin.LogFormatterParams) string {
+		var statusColor, methodColor, resetColor string
+		if params.IsOutputColor() {
+			statusColor = params.StatusCodeColor()
+			methodColor = params.MethodColor()
+			resetColor = params.ResetColor()
+		}
+		if params.Latency > time.Minute {
+			params.Latency = params.Latency.Truncate(time.Second)
+		}
+		return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+			params.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			statusColor, params.StatusCode, resetColor,
+			params.Latency,
+			params.ClientIP,
+			methodColor, params.Method, resetColor,
+			redactToken(params.Path),
+			params.ErrorMessage,
+		)
+	})
+}
+
+// redactToken replaces the value of the token query parameter of path.
+func redactToken(path string) string {
+	path, rawQuery, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// The query cannot be told apart reliably, so none of it is logged.
+		return path + "?REDACTED"
+	}
+	if !query.Has("token") {
+		return path + "?" + rawQuery
+	}
+	for i := range query["token"] {
+		query["token"][i] = "REDACTED"
+	}
+	return path + "?" + query.Encode()
+}
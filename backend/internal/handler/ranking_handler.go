@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+	"job-parser-backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RankingHandler interface {
+	getRankedJobsHandler(context *gin.Context)
+	registerRankingHandler(router *gin.Engine)
+}
+
+type rankingHandler struct {
+	service service.RankingService
+}
+
+func CreateRankingHandler(svc service.RankingService, router *gin.Engine) RankingHandler {
+	rankingHandler := &rankingHandler{service: svc}
+	rankingHandler.registerRankingHandler(router)
+	return rankingHandler
+}
+
+func (h *rankingHandler) registerRankingHandler(router *gin.Engine) {
+	router.GET("/api/job/ranked", h.getRankedJobsHandler)
+}
+
+func (h *rankingHandler) getRankedJobsHandler(context *gin.Context) {
+	ranked, err := h.service.RankJobs(context.Request.Context(), context.Query("resumeId"))
+	if errors.Is(err, service.ErrResumeNotFound) || errors.Is(err, service.ErrNoResume) {
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rank jobs"})
+		return
+	}
+	context.JSON(http.StatusOK, ranked)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"fmt"
+	"job-parser-backend/internal/auth"
+	"job-parser-backend/internal/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// llmRoutes call a model on each request, so they share the smaller LLM
+// budget. Every other route counts against the read budget.
+var llmRoutes = map[string]bool{
+	"POST /api/job":                            true,
+	"POST /api/job/compare":                    true,
+	"POST /api/resume/parse":                   true,
+	"POST /api/job/:pageID/cover-letter":       true,
+	"POST /api/job/:pageID/interview-prep":     true,
+	"POST /api/job/:pageID/resume-suggestions": true,
+	"GET /api/job/ranked":                      true,
+}
+
+// RateLimit is middleware that limits each client's /api requests, with
+// separate budgets for LLM routes and the rest. Clients are told apart by
+// API key, or by IP when the request is not authenticated. It must be added
+// after authentication and before the routes.
+func RateLimit(llm ratelimit.Limiter, read ratelimit.Limiter) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if !strings.HasPrefix(context.Request.URL.Path, "/api/") {
+			context.Next()
+			return
+		}
+
+		limiter, budget := read, "read"
+		if llmRoutes[context.Request.Method+" "+context.FullPath()] {
+			limiter, budget = llm, "LLM"
+		}
+		client := "ip:" + context.ClientIP()
+		if principal := auth.FromContext(context.Request.Context()); principal != nil {
+			client = "key:" + principal.KeyID
+		}
+
+		if ok, wait := limiter.Allow(client); !ok {
+			tooManyRequests(context, budget, wait)
+			return
+		}
+		context.Next()
+	}
+}
+
+// IPRateLimit is middleware that limits the /api requests of each IP before
+// they are authenticated, so clients without a valid key cannot make
+// unlimited attempts. It must be added before authentication.
+func IPRateLimit(limiter ratelimit.Limiter) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if !strings.HasPrefix(context.Request.URL.Path, "/api/") {
+			context.Next()
+			return
+		}
+
+		if ok, wait := limiter.Allow("ip:" + context.ClientIP()); !ok {
+			tooManyRequests(context, "IP", wait)
+			return
+		}
+		context.Next()
+	}
+}
+
+func tooManyRequests(context *gin.Context, budget string, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	context.Header("Retry-After", strconv.Itoa(seconds))
+	context.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("Rate limit for %s requests exceeded, retry in %ds", budget, seconds)})
+}
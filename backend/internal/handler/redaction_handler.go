@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"job-parser-backend/internal/redact"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RedactionHandler interface {
+	getRedactionAuditHandler(context *gin.Context)
+	registerRedactionHandler(router *gin.Engine)
+}
+
+type redactionHandler struct {
+	redactor redact.Redactor
+}
+
+func CreateRedactionHandler(redactor redact.Redactor, router *gin.Engine) RedactionHandler {
+	redactionHandler := &redactionHandler{redactor: redactor}
+	redactionHandler.registerRedactionHandler(router)
+	return redactionHandler
+}
+
+func (h *redactionHandler) registerRedactionHandler(router *gin.Engine) {
+	router.GET("/api/audit/redactions", h.getRedactionAuditHandler)
+}
+
+// getRedactionAuditHandler lists what was redacted from LLM requests between
+// from and to (YYYY-MM-DD, inclusive) for the user making the request,
+// defaulting to the current month so far. It needs only the read scope, as
+// each user sees their own entries and no one else's.
+func (h *redactionHandler) getRedactionAuditHandler(context *gin.Context) {
+	from, to, ok := dateRange(context)
+	if !ok {
+		return
+	}
+
+	entries, err := h.redactor.Audit(context.Request.Context(), from, to)
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read redaction audit"})
+		return
+	}
+	context.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"job-parser-backend/internal/report"
+	"job-parser-backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler interface {
+	getWeeklyReportHandler(context *gin.Context)
+	registerReportHandler(router *gin.Engine)
+}
+
+type reportHandler struct {
+	service service.ReportService
+}
+
+func CreateReportHandler(svc service.ReportService, router *gin.Engine) ReportHandler {
+	reportHandler := &reportHandler{service: svc}
+	reportHandler.registerReportHandler(router)
+	return reportHandler
+}
+
+func (h *reportHandler) registerReportHandler(router *gin.Engine) {
+	router.GET("/api/report/weekly", h.getWeeklyReportHandler)
+}
+
+// getWeeklyReportHandler returns the digest as JSON, or rendered when
+// format is "html" or "markdown".
+func (h *reportHandler) getWeeklyReportHandler(context *gin.Context) {
+	format := context.DefaultQuery("format", "json")
+
+	var contentType string
+	switch format {
+	case "json":
+	case report.FormatHTML:
+		contentType = "text/html; charset=utf-8"
+	case report.FormatMarkdown:
+		contentType = "text/markdown; charset=utf-8"
+	default:
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported report format"})
+		return
+	}
+
+	weekly, err := h.service.WeeklyReport(context.Request.Context())
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build weekly report"})
+		return
+	}
+
+	if format == "json" {
+		context.JSON(http.StatusOK, weekly)
+		return
+	}
+
+	rendered, err := report.RenderWeekly(weekly, format)
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render weekly report"})
+		return
+	}
+
+	context.Data(http.StatusOK, contentType, []byte(rendered))
+}
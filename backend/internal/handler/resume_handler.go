@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/service"
+	"job-parser-backend/internal/usage"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxResumeSize is the largest resume upload accepted, in bytes.
+const maxResumeSize = 5 << 20
+
+type ResumeHandler interface {
+	parseResumeHandler(context *gin.Context)
+	createResumeHandler(context *gin.Context)
+	listResumesHandler(context *gin.Context)
+	getResumeHandler(context *gin.Context)
+	getResumeVersionHandler(context *gin.Context)
+	updateResumeHandler(context *gin.Context)
+	registerResumeHandler(router *gin.Engine)
+}
+
+type resumeHandler struct {
+	service service.ResumeService
+}
+
+func CreateResumeHandler(svc service.ResumeService, router *gin.Engine) ResumeHandler {
+	resumeHandler := &resumeHandler{service: svc}
+	resumeHandler.registerResumeHandler(router)
+	return resumeHandler
+}
+
+func (h *resumeHandler) registerResumeHandler(router *gin.Engine) {
+	router.GET("/api/resume", h.listResumesHandler)
+	router.GET("/api/resume/:id", h.getResumeHandler)
+	router.GET("/api/resume/:id/versions/:version", h.getResumeVersionHandler)
+
+	router.POST("/api/resume", h.createResumeHandler)
+	router.POST("/api/resume/parse", h.parseResumeHandler)
+
+	router.PUT("/api/resume/:id", h.updateResumeHandler)
+}
+
+// parseResumeHandler accepts a multipart upload with the resume in the
+// "file" field and returns it as a structured model.Resume.
+func (h *resumeHandler) parseResumeHandler(context *gin.Context) {
+	context.Request.Body = http.MaxBytesReader(context.Writer, context.Request.Body, maxResumeSize+1<<20)
+
+	fileHeader, err := context.FormFile("file")
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Missing resume file"})
+		return
+	}
+
+	if fileHeader.Size > maxResumeSize {
+		context.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Resume file is too large"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resume file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resume file"})
+		return
+	}
+
+	resume, err := h.service.ParseResume(context.Request.Context(), fileHeader.Filename, data)
+	if errors.Is(err, usage.ErrBudgetExceeded) {
+		context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to parse resume"})
+		return
+	}
+
+	context.JSON(http.StatusOK, resume)
+}
+
+func (h *resumeHandler) createResumeHandler(context *gin.Context) {
+	type CreateResumeRequest struct {
+		Name    string        `json:"name"`
+		Default bool          `json:"default"`
+		Resume  *model.Resume `json:"resume"`
+	}
+	var req CreateResumeRequest
+	if err := context.BindJSON(&req); err != nil {
+		logError(err)
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if req.Name == "" || req.Resume == nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Name and resume are required"})
+		return
+	}
+
+	res, err := h.service.CreateResume(context.Request.Context(), req.Name, *req.Resume, req.Default)
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save resume"})
+		return
+	}
+	context.JSON(http.StatusCreated, res)
+}
+
+func (h *resumeHandler) listResumesHandler(context *gin.Context) {
+	resumes, err := h.service.ListResumes(context.Request.Context())
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch resumes"})
+		return
+	}
+	context.JSON(http.StatusOK, resumes)
+}
+
+func (h *resumeHandler) getResumeHandler(context *gin.Context) {
+	resume, err := h.service.GetResume(context.Request.Context(), context.Param("id"))
+	if errors.Is(err, service.ErrResumeNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"error": "Resume not found"})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch resume"})
+		return
+	}
+	context.JSON(http.StatusOK, resume)
+}
+
+func (h *resumeHandler) getResumeVersionHandler(context *gin.Context) {
+	version, err := strconv.Atoi(context.Param("version"))
+	if err != nil || version < 1 {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resume version"})
+		return
+	}
+
+	resume, err := h.service.GetResumeVersion(context.Request.Context(), context.Param("id"), version)
+	if errors.Is(err, service.ErrResumeNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"error": "Resume not found"})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch resume"})
+		return
+	}
+	context.JSON(http.StatusOK, resume)
+}
+
+// updateResumeHandler renames a resume, adds a new version when a resume body
+// is sent, and makes it the default when "default" is true.
+func (h *resumeHandler) updateResumeHandler(context *gin.Context) {
+	type UpdateResumeRequest struct {
+		Name    string        `json:"name"`
+		Default bool          `json:"default"`
+		Resume  *model.Resume `json:"resume"`
+	}
+	var req UpdateResumeRequest
+	if err := context.BindJSON(&req); err != nil {
+		logError(err)
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	res, err := h.service.UpdateResume(context.Request.Context(), context.Param("id"), req.Name, req.Resume, req.Default)
+	if errors.Is(err, service.ErrResumeNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"error": "Resume not found"})
+		return
+	}
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update resume"})
+		return
+	}
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/service"
+	"job-parser-backend/internal/usage"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SuggestionHandler interface {
+	suggestBulletRewritesHandler(context *gin.Context)
+	registerSuggestionHandler(router *gin.Engine)
+}
+
+type suggestionHandler struct {
+	service service.SuggestionService
+}
+
+func CreateSuggestionHandler(svc service.SuggestionService, router *gin.Engine) SuggestionHandler {
+	suggestionHandler := &suggestionHandler{service: svc}
+	suggestionHandler.registerSuggestionHandler(router)
+	return suggestionHandler
+}
+
+func (h *suggestionHandler) registerSuggestionHandler(router *gin.Engine) {
+	router.POST("/api/job/:pageID/resume-suggestions", h.suggestBulletRewritesHandler)
+}
+
+// suggestBulletRewritesHandler accepts an optional body selecting the resume
+// with resumeId/resumeVersion or an inline resume; the default resume is
+// used otherwise.
+func (h *suggestionHandler) suggestBulletRewritesHandler(context *gin.Context) {
+	var req model.CompareRequest
+	if err := context.ShouldBindJSON(&req); err != nil && context.Request.ContentLength > 0 {
+		logError(err)
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	suggestions, err := h.service.SuggestBulletRewrites(context.Request.Context(), context.Param("pageID"), req)
+	switch {
+	case errors.Is(err, service.ErrNoJobPosting):
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrResumeNotFound), errors.Is(err, service.ErrNoResume):
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, usage.ErrBudgetExceeded):
+		context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate resume suggestions"})
+		return
+	}
+	context.JSON(http.StatusOK, suggestions)
+}
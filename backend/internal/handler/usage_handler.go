@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"job-parser-backend/internal/usage"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageHandler interface {
+	getUsageHandler(context *gin.Context)
+	registerUsageHandler(router *gin.Engine)
+}
+
+type usageHandler struct {
+	meter usage.Meter
+}
+
+func CreateUsageHandler(meter usage.Meter, router *gin.Engine) UsageHandler {
+	usageHandler := &usageHandler{meter: meter}
+	usageHandler.registerUsageHandler(router)
+	return usageHandler
+}
+
+func (h *usageHandler) registerUsageHandler(router *gin.Engine) {
+	router.GET("/api/usage", h.getUsageHandler)
+}
+
+// getUsageHandler returns daily LLM usage and cost between from and to
+// (YYYY-MM-DD, inclusive), defaulting to the current month so far, for the
+// user making the request. jobId limits the report to one job's calls.
+func (h *usageHandler) getUsageHandler(context *gin.Context) {
+	from, to, ok := dateRange(context)
+	if !ok {
+		return
+	}
+
+	report, err := h.meter.Report(context.Request.Context(), from, to, context.Query("jobId"))
+	if err != nil {
+		logError(err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build usage report"})
+		return
+	}
+	context.JSON(http.StatusOK, report)
+}
+
+// dateRange reads the from and to query parameters (YYYY-MM-DD, inclusive),
+// defaulting to the current month so far. It responds with 400 and returns
+// false when they are invalid.
+func dateRange(context *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	to := now
+
+	if value := context.Query("from"); value != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, value, now.Location())
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return from, to, false
+		}
+		from = parsed
+	}
+	if value := context.Query("to"); value != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, value, now.Location())
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return from, to, false
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return from, to, false
+	}
+
+	return from, to, true
+}
@@ -0,0 +1,282 @@
+package llmcache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"job-parser-backend/internal/utils"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores model replies by the prompt version, model and input that
+// produced them, so repeating a call with the same content is free.
+type Cache interface {
+	Get(ctx context.Context, key Key) (string, bool)
+	Put(key Key, content string)
+	Stats() Stats
+}
+
+// Key identifies an LLM call. Input is normalized so whitespace differences
+// in the same posting or resume still hit.
+type Key struct {
+	PromptVersion string
+	Model         string
+	Input         string
+}
+
+// NewKey builds a key from the messages of a call. System messages are
+// represented by promptVersion instead of their text.
+func NewKey(promptVersion string, model string, messages []map[string]string) Key {
+	var input strings.Builder
+	for _, message := range messages {
+		if message["role"] == "system" {
+			continue
+		}
+		input.WriteString(message["role"])
+		input.WriteString(": ")
+		input.WriteString(strings.Join(strings.Fields(message["content"]), " "))
+		input.WriteString("\n")
+	}
+	return Key{PromptVersion: promptVersion, Model: model, Input: input.String()}
+}
+
+func (k Key) hash() string {
+	return utils.ContentHash(k.PromptVersion + "\x00" + k.Model + "\x00" + k.Input)
+}
+
+// prompt is the prompt name part of the version, used to group metrics.
+func (k Key) prompt() string {
+	name, _, _ := strings.Cut(k.PromptVersion, "@")
+	return name
+}
+
+type bypassKey struct{}
+
+// WithBypass marks ctx so lookups miss and fresh replies replace the
+// cached ones.
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+func Bypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassKey{}).(bool)
+	return bypass
+}
+
+// Stats counts lookups since the server started, in total and per prompt.
+type Stats struct {
+	Counts
+	Entries   int               `json:"entries"`
+	Evictions int64             `json:"evictions"`
+	ByPrompt  map[string]Counts `json:"byPrompt"`
+}
+
+type Counts struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	Bypassed int64   `json:"bypassed"`
+	HitRate  float64 `json:"hitRate"`
+}
+
+type entry struct {
+	key       string
+	content   string
+	createdAt time.Time
+}
+
+// diskEntry is the file format of persisted entries.
+type diskEntry struct {
+	PromptVersion string    `json:"promptVersion"`
+	Model         string    `json:"model"`
+	Content       string    `json:"content"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type cache struct {
+	size int
+	ttl  time.Duration
+	dir  string
+
+	mu        sync.Mutex
+	order     *list.List
+	entries   map[string]*list.Element
+	counts    map[string]*Counts
+	evictions int64
+}
+
+// NewCache creates an LRU cache of size entries whose entries expire after
+// ttl. When dir is not empty entries are also written there, one file each,
+// so they survive restarts; expired files are removed on startup.
+func NewCache(size int, ttl time.Duration, dir string) (Cache, error) {
+	c := &cache{
+		size:    size,
+		ttl:     ttl,
+		dir:     dir,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		counts:  make(map[string]*Counts),
+	}
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("error creating LLM cache directory: %w", err)
+		}
+		c.prune()
+	}
+
+	return c, nil
+}
+
+func (c *cache) Get(ctx context.Context, key Key) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := c.countsFor(key)
+	if Bypassed(ctx) {
+		counts.Bypassed++
+		return "", false
+	}
+
+	hash := key.hash()
+	if element, ok := c.entries[hash]; ok {
+		e := element.Value.(*entry)
+		if time.Since(e.createdAt) < c.ttl {
+			c.order.MoveToFront(element)
+			counts.Hits++
+			return e.content, true
+		}
+		c.order.Remove(element)
+		delete(c.entries, hash)
+	}
+
+	if e, ok := c.readDisk(hash); ok {
+		c.add(e)
+		counts.Hits++
+		return e.content, true
+	}
+
+	counts.Misses++
+	return "", false
+}
+
+func (c *cache) Put(key Key, content string) {
+	e := &entry{key: key.hash(), content: content, createdAt: time.Now()}
+
+	c.mu.Lock()
+	if element, ok := c.entries[e.key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, e.key)
+	}
+	c.add(e)
+	c.mu.Unlock()
+
+	if c.dir != "" {
+		if err := c.writeDisk(key, e); err != nil {
+			log.Printf("Failed to persist LLM cache entry: %v", err)
+		}
+	}
+}
+
+func (c *cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := Stats{
+		Entries:   c.order.Len(),
+		Evictions: c.evictions,
+		ByPrompt:  make(map[string]Counts, len(c.counts)),
+	}
+	for name, counts := range c.counts {
+		stats.ByPrompt[name] = withHitRate(*counts)
+		stats.Hits += counts.Hits
+		stats.Misses += counts.Misses
+		stats.Bypassed += counts.Bypassed
+	}
+	stats.Counts = withHitRate(stats.Counts)
+	return stats
+}
+
+func withHitRate(counts Counts) Counts {
+	if lookups := counts.Hits + counts.Misses + counts.Bypassed; lookups > 0 {
+		counts.HitRate = float64(counts.Hits) / float64(lookups)
+	}
+	return counts
+}
+
+func (c *cache) countsFor(key Key) *Counts {
+	counts, ok := c.counts[key.prompt()]
+	if !ok {
+		counts = &Counts{}
+		c.counts[key.prompt()] = counts
+	}
+	return counts
+}
+
+// add inserts e as the most recently used entry, evicting the least
+// recently used one when the cache is full. Evicted entries stay on disk.
+func (c *cache) add(e *entry) {
+	c.entries[e.key] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+		c.evictions++
+	}
+}
+
+func (c *cache) path(hash string) string {
+	return filepath.Join(c.dir, hash+".json")
+}
+
+func (c *cache) readDisk(hash string) (*entry, bool) {
+	if c.dir == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.path(hash))
+	if err != nil {
+		return nil, false
+	}
+
+	var stored diskEntry
+	if err := json.Unmarshal(data, &stored); err != nil || time.Since(stored.CreatedAt) >= c.ttl {
+		os.Remove(c.path(hash))
+		return nil, false
+	}
+
+	return &entry{key: hash, content: stored.Content, createdAt: stored.CreatedAt}, true
+}
+
+func (c *cache) writeDisk(key Key, e *entry) error {
+	data, err := json.Marshal(diskEntry{
+		PromptVersion: key.PromptVersion,
+		Model:         key.Model,
+		Content:       e.content,
+		CreatedAt:     e.createdAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path(e.key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path(e.key))
+}
+
+func (c *cache) prune() {
+	paths, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return
+	}
+	for _, path := range paths {
+		c.readDisk(strings.TrimSuffix(filepath.Base(path), ".json"))
+	}
+}
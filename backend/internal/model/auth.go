@@ -0,0 +1,22 @@
+package model
+
+// APIKey is an API key as stored. Only a hash of its secret is kept, so a
+// lost key cannot be recovered, only revoked and replaced. User is who the
+// key acts for when the server serves several users, and empty for the
+// server's owner.
+type APIKey struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	User      string   `json:"user,omitempty"`
+	Scopes    []string `json:"scopes"`
+	Hash      string   `json:"hash"`
+	CreatedAt string   `json:"createdAt"`
+	RevokedAt string   `json:"revokedAt,omitempty"`
+}
+
+// AccessToken is a short-lived token issued for an API key.
+type AccessToken struct {
+	Token     string   `json:"token"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt string   `json:"expiresAt"`
+}
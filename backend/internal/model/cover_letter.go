@@ -0,0 +1,41 @@
+package model
+
+// CoverLetterSettings is the user's default style for generated cover
+// letters. Instructions is free text added to the prompt, e.g. "mention that
+// I am open to relocation".
+type CoverLetterSettings struct {
+	Tone         string `json:"tone"`
+	Length       string `json:"length"`
+	Instructions string `json:"instructions"`
+}
+
+// CoverLetterRequest generates a cover letter for a job. Empty fields fall
+// back to the stored settings and the default resume.
+type CoverLetterRequest struct {
+	ResumeID      string `json:"resumeId"`
+	ResumeVersion int    `json:"resumeVersion"`
+	Tone          string `json:"tone"`
+	Length        string `json:"length"`
+	Instructions  string `json:"instructions"`
+}
+
+// CoverLetter is one saved draft. Drafts for a job are numbered from 1 and
+// never overwritten. Content is Markdown.
+type CoverLetter struct {
+	JobID         string `json:"jobId"`
+	Version       int    `json:"version"`
+	ResumeID      string `json:"resumeId,omitempty"`
+	ResumeVersion int    `json:"resumeVersion,omitempty"`
+	Tone          string `json:"tone"`
+	Length        string `json:"length"`
+	Content       string `json:"content"`
+	Model         string `json:"model"`
+	PromptVersion string `json:"promptVersion"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+const (
+	CoverLetterShort  string = "short"
+	CoverLetterMedium string = "medium"
+	CoverLetterLong   string = "long"
+)
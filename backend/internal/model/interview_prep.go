@@ -0,0 +1,36 @@
+package model
+
+// InterviewPrep is a preparation pack generated for a job once it reaches
+// the interview stage.
+type InterviewPrep struct {
+	JobID               string         `json:"jobId"`
+	ResumeID            string         `json:"resumeId,omitempty"`
+	ResumeVersion       int            `json:"resumeVersion,omitempty"`
+	TechnicalQuestions  []PrepQuestion `json:"technicalQuestions"`
+	BehavioralQuestions []PrepQuestion `json:"behavioralQuestions"`
+	StarStories         []StarStory    `json:"starStories"`
+	QuestionsToAsk      []string       `json:"questionsToAsk"`
+	Model               string         `json:"model"`
+	PromptVersion       string         `json:"promptVersion"`
+	CreatedAt           string         `json:"createdAt"`
+}
+
+// PrepQuestion is a likely interview question and the part of the posting
+// it was derived from.
+type PrepQuestion struct {
+	Question string `json:"question"`
+	Why      string `json:"why"`
+}
+
+// StarStory outlines an answer in Situation, Task, Action, Result form based
+// on an entry of the resume's experience list.
+type StarStory struct {
+	Question        string `json:"question"`
+	ExperienceIndex int    `json:"experienceIndex"`
+	Company         string `json:"company"`
+	Title           string `json:"title"`
+	Situation       string `json:"situation"`
+	Task            string `json:"task"`
+	Action          string `json:"action"`
+	Result          string `json:"result"`
+}
@@ -9,6 +9,60 @@ type Job struct {
 	URL         string `json:"url"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
+
+	CreatedDate   string          `json:"createdDate,omitempty"`
+	AppliedDate   string          `json:"appliedDate,omitempty"`
+	StatusHistory []StatusChange  `json:"statusHistory,omitempty"`
+	Events        []JobEvent      `json:"events,omitempty"`
+	Comparisons   []JobComparison `json:"comparisons,omitempty"`
+	CoverLetters  []CoverLetter   `json:"coverLetters,omitempty"`
+	InterviewPrep *InterviewPrep  `json:"interviewPrep,omitempty"`
+	Extraction    *Extraction     `json:"extraction,omitempty"`
+}
+
+// Extraction records how a saved job's fields were extracted from the
+// posting text.
+type Extraction struct {
+	Provider      string   `json:"provider,omitempty"`
+	Model         string   `json:"model"`
+	PromptVersion string   `json:"promptVersion"`
+	ExtractedAt   string   `json:"extractedAt"`
+	InputTokens   int      `json:"inputTokens,omitempty"`
+	Chunks        int      `json:"chunks,omitempty"`
+	Flags         []string `json:"flags,omitempty"`
+}
+
+// Flags raised on LLM results whose input looked manipulated, so they can be
+// reviewed before they are trusted.
+const (
+	// FlagPromptInjection marks results for postings that contain text
+	// addressed to a language model, such as "ignore previous instructions".
+	FlagPromptInjection = "prompt_injection"
+	// FlagCompanyNotInSource marks extractions whose company did not appear
+	// in the posting or its URL. The company is discarded.
+	FlagCompanyNotInSource = "company_not_in_source"
+)
+
+// JobEvent is a dated milestone for a job such as an interview or deadline.
+// Start and End are ISO 8601 dates or date-times; a date-only Start is an
+// all-day event. TimeZone is an IANA name used when Start has no offset.
+type JobEvent struct {
+	Type     string `json:"type"`
+	Start    string `json:"start"`
+	End      string `json:"end,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+const (
+	EventInterview string = "interview"
+	EventDeadline  string = "deadline"
+	EventFollowUp  string = "follow_up"
+)
+
+// StatusChange records a status transition made through UpdateJob.
+type StatusChange struct {
+	Status    string `json:"status"`
+	ChangedAt string `json:"changedAt"`
 }
 
 // StatsResult holds aggregated job application statistics.
@@ -28,8 +82,58 @@ type StreakStats struct {
 }
 
 // JobComparison holds comparison results between a resume and job posting.
+// MatchScore is the model's score. KeywordMatch is the deterministic
+// taxonomy-based score for the same inputs, and ScoreDifference is MatchScore
+// minus KeywordMatch.Score, so large values show where the model disagrees
+// with the hard evidence. ResumeHash and PostingHash fingerprint the inputs
+// so a stored comparison is only recomputed when the resume or the posting
+// changes.
 type JobComparison struct {
-	MatchScore      int      `json:"matchScore"`
-	MissingSkills   []string `json:"missingSkills"`
-	Recommendations []string `json:"recommendations"`
+	MatchScore      int         `json:"matchScore"`
+	KeywordMatch    *SkillMatch `json:"keywordMatch,omitempty"`
+	ScoreDifference int         `json:"scoreDifference"`
+	MissingSkills   []string    `json:"missingSkills"`
+	ExperienceGap   []string    `json:"experienceGap"`
+	Recommendations []string    `json:"recommendations"`
+	JobID           string      `json:"jobId,omitempty"`
+	ResumeID        string      `json:"resumeId,omitempty"`
+	ResumeVersion   int         `json:"resumeVersion,omitempty"`
+	ResumeHash      string      `json:"resumeHash,omitempty"`
+	PostingHash     string      `json:"postingHash,omitempty"`
+	Provider        string      `json:"provider,omitempty"`
+	Model           string      `json:"model,omitempty"`
+	PromptVersion   string      `json:"promptVersion,omitempty"`
+	ComparedAt      string      `json:"comparedAt,omitempty"`
+	Flags           []string    `json:"flags,omitempty"`
+	Cached          bool        `json:"cached,omitempty"`
+}
+
+// SkillMatch is the deterministic, taxonomy-based comparison of a resume and
+// a posting. Score is MatchedWeight as a percentage of TotalWeight.
+type SkillMatch struct {
+	Score           int      `json:"score"`
+	Matched         []string `json:"matched"`
+	Missing         []string `json:"missing"`
+	MatchedWeight   int      `json:"matchedWeight"`
+	TotalWeight     int      `json:"totalWeight"`
+	ResumeSkills    []string `json:"resumeSkills"`
+	PostingSkills   []string `json:"postingSkills"`
+	TaxonomyVersion string   `json:"taxonomyVersion"`
+}
+
+// RankedJob is an unapplied job scored for how worthwhile applying is now.
+// Priority combines FitScore with how fresh the posting is and how close its
+// deadline is; Explanation lists each factor in plain words. Pending is set
+// while the model comparison is still being computed in the background, in
+// which case FitScore is the keyword score alone.
+type RankedJob struct {
+	Job            Job      `json:"job"`
+	Priority       int      `json:"priority"`
+	FitScore       int      `json:"fitScore"`
+	LLMScore       *int     `json:"llmScore,omitempty"`
+	KeywordScore   int      `json:"keywordScore"`
+	AgeDays        int      `json:"ageDays"`
+	DeadlineInDays *int     `json:"deadlineInDays,omitempty"`
+	Pending        bool     `json:"pending"`
+	Explanation    []string `json:"explanation"`
 }
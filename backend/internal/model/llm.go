@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// ChatResponse is the response structure of an OpenAI-compatible chat
+// completions API such as Groq's. Latency is measured by the client.
+type ChatResponse struct {
+	Model   string        `json:"model"`
+	Choices []ChatChoice  `json:"choices"`
+	Usage   ChatUsage     `json:"usage"`
+	Latency time.Duration `json:"-"`
+}
+
+type ChatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type ChatChoice struct {
+	Message ChatMessage `json:"message"`
+}
+
+type ChatMessage struct {
+	Content string `json:"content"`
+}
+
+const (
+	Mixtral_Saba_24b   string = "mistral-saba-24b"
+	Gemma2_9B_Instruct string = "gemma2-9b-it"
+)
@@ -0,0 +1,29 @@
+package model
+
+// Notification is a message delivered through the configured notifiers.
+// Payload carries the structured data behind the message for webhook
+// consumers.
+type Notification struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html,omitempty"`
+	Payload any    `json:"payload,omitempty"`
+}
+
+// Reminder flags a job that has sat in the same status for too long.
+type Reminder struct {
+	JobID        string `json:"jobId"`
+	Title        string `json:"title"`
+	Company      string `json:"company"`
+	URL          string `json:"url"`
+	Status       string `json:"status"`
+	Since        string `json:"since"`
+	DaysInStatus int    `json:"daysInStatus"`
+	SentAt       string `json:"sentAt,omitempty"`
+}
+
+const (
+	NotificationReminder     string = "reminder"
+	NotificationWeeklyDigest string = "weekly_digest"
+)
@@ -2,12 +2,11 @@ package model
 
 // NotionProperties maps the structured properties of a Notion job entry.
 type NotionProperties struct {
-	AppliedDate struct {
-		Date *struct {
-			Start string `json:"start"`
-		} `json:"date"`
-	} `json:"Applied Date"`
-	Status struct {
+	AppliedDate   NotionDate `json:"Applied Date"`
+	InterviewDate NotionDate `json:"Interview Date"`
+	Deadline      NotionDate `json:"Deadline"`
+	FollowUpDate  NotionDate `json:"Follow Up Date"`
+	Status        struct {
 		Status struct {
 			Name string `json:"name"`
 		} `json:"status"`
@@ -37,16 +36,30 @@ type NotionProperties struct {
 	} `json:"Description"`
 }
 
+// NotionDate is a Notion date property. Date is nil when the property is empty.
+type NotionDate struct {
+	Date *struct {
+		Start    string `json:"start"`
+		End      string `json:"end"`
+		TimeZone string `json:"time_zone"`
+	} `json:"date"`
+}
+
 // NotionPage represents a single Notion page with job properties.
 type NotionPage struct {
-	Properties NotionProperties `json:"properties"`
+	ID          string           `json:"id"`
+	CreatedTime string           `json:"created_time"`
+	Properties  NotionProperties `json:"properties"`
 }
 
 // NotionResponse represents the full response from a Notion API query.
 type NotionResponse struct {
 	Results []struct {
-		ID         string           `json:"id"`
-		Link       string           `json:"link"`
-		Properties NotionProperties `json:"properties"`
+		ID          string           `json:"id"`
+		Link        string           `json:"link"`
+		CreatedTime string           `json:"created_time"`
+		Properties  NotionProperties `json:"properties"`
 	} `json:"results"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor"`
 }
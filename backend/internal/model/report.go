@@ -0,0 +1,29 @@
+package model
+
+// WeeklyReport summarizes job hunting activity over the past seven days.
+type WeeklyReport struct {
+	PeriodStart       string              `json:"periodStart"`
+	PeriodEnd         string              `json:"periodEnd"`
+	ApplicationsSent  int                 `json:"applicationsSent"`
+	DailyCount        map[string]int      `json:"dailyCount"`
+	StatusCount       map[string]int      `json:"statusCount"`
+	Streak            StreakStats         `json:"streak"`
+	StatusChanges     []ReportStatusEntry `json:"statusChanges"`
+	StaleApplications []Reminder          `json:"staleApplications"`
+	TopCompanies      []CompanyCount      `json:"topCompanies"`
+}
+
+// ReportStatusEntry is a status change made during the report period.
+type ReportStatusEntry struct {
+	JobID     string `json:"jobId"`
+	Title     string `json:"title"`
+	Company   string `json:"company"`
+	Status    string `json:"status"`
+	ChangedAt string `json:"changedAt"`
+}
+
+// CompanyCount is the number of jobs saved for a company.
+type CompanyCount struct {
+	Company string `json:"company"`
+	Count   int    `json:"count"`
+}
@@ -0,0 +1,130 @@
+package model
+
+import "encoding/json"
+
+// Resume is the structured form of a candidate's resume. Dates are kept as
+// written ("2021-03", "Mar 2021", "Present") because resumes rarely use a
+// consistent format.
+type Resume struct {
+	Contact        ResumeContact     `json:"contact"`
+	Summary        string            `json:"summary"`
+	Experience     []ExperienceEntry `json:"experience"`
+	Skills         []string          `json:"skills"`
+	Education      []EducationEntry  `json:"education"`
+	Certifications []Certification   `json:"certifications"`
+}
+
+// ResumeContact holds the candidate's contact details.
+type ResumeContact struct {
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Phone    string   `json:"phone"`
+	Location string   `json:"location"`
+	Links    []string `json:"links"`
+}
+
+// ExperienceEntry is a single position on the resume.
+type ExperienceEntry struct {
+	Company   string   `json:"company"`
+	Title     string   `json:"title"`
+	Location  string   `json:"location"`
+	StartDate string   `json:"startDate"`
+	EndDate   string   `json:"endDate"`
+	Current   bool     `json:"current"`
+	Bullets   []string `json:"bullets"`
+}
+
+// EducationEntry is a degree or course of study.
+type EducationEntry struct {
+	Institution string `json:"institution"`
+	Degree      string `json:"degree"`
+	Field       string `json:"field"`
+	StartDate   string `json:"startDate"`
+	EndDate     string `json:"endDate"`
+}
+
+// Certification is a professional certification or license.
+type Certification struct {
+	Name   string `json:"name"`
+	Issuer string `json:"issuer"`
+	Date   string `json:"date"`
+}
+
+// ParsedResume is a resume structured from an uploaded file, with the model
+// and prompt version that structured it.
+type ParsedResume struct {
+	Resume
+	Model         string `json:"model"`
+	PromptVersion string `json:"promptVersion"`
+}
+
+// StoredResume is a named resume kept on the server. Every update appends a
+// new version so past comparisons can be traced to the exact text used.
+type StoredResume struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Default   bool            `json:"default"`
+	CreatedAt string          `json:"createdAt"`
+	UpdatedAt string          `json:"updatedAt"`
+	Versions  []ResumeVersion `json:"versions"`
+}
+
+// ResumeVersion is one immutable revision of a stored resume.
+type ResumeVersion struct {
+	ResumeID  string `json:"resumeId"`
+	Version   int    `json:"version"`
+	CreatedAt string `json:"createdAt"`
+	Resume    Resume `json:"resume"`
+}
+
+// ResumeSummary describes a stored resume without its version contents.
+type ResumeSummary struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Default       bool   `json:"default"`
+	LatestVersion int    `json:"latestVersion"`
+	UpdatedAt     string `json:"updatedAt"`
+}
+
+// CompareRequest selects the resume to compare against a job posting: an
+// inline Resume, a stored ResumeID and optional ResumeVersion (latest when
+// zero), or the default stored resume when neither is given. When JobID is
+// set the result is stored on that job, and JobPosting defaults to the saved
+// description. Refresh forces a new comparison even if a stored one matches.
+type CompareRequest struct {
+	JobID         string  `json:"jobId"`
+	ResumeID      string  `json:"resumeId"`
+	ResumeVersion int     `json:"resumeVersion"`
+	Resume        *Resume `json:"resume"`
+	JobPosting    string  `json:"jobPosting"`
+	Refresh       bool    `json:"refresh"`
+}
+
+// UnmarshalJSON also accepts the resume as plain text, as the extension sent
+// it before resumes were structured. The text is kept whole as the summary.
+func (r *CompareRequest) UnmarshalJSON(data []byte) error {
+	type compareRequest CompareRequest
+	request := struct {
+		*compareRequest
+		Resume json.RawMessage `json:"resume"`
+	}{compareRequest: (*compareRequest)(r)}
+	if err := json.Unmarshal(data, &request); err != nil {
+		return err
+	}
+
+	r.Resume = nil
+	var text string
+	switch {
+	case len(request.Resume) == 0 || string(request.Resume) == "null":
+	case json.Unmarshal(request.Resume, &text) == nil:
+		if text != "" {
+			r.Resume = &Resume{Summary: text}
+		}
+	default:
+		r.Resume = &Resume{}
+		if err := json.Unmarshal(request.Resume, r.Resume); err != nil {
+			return err
+		}
+	}
+	return nil
+}
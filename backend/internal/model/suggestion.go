@@ -0,0 +1,40 @@
+package model
+
+// ResumeSuggestions are rewritten resume bullets targeted at one job.
+type ResumeSuggestions struct {
+	JobID         string             `json:"jobId"`
+	ResumeID      string             `json:"resumeId,omitempty"`
+	ResumeVersion int                `json:"resumeVersion,omitempty"`
+	Suggestions   []BulletSuggestion `json:"suggestions"`
+	Model         string             `json:"model"`
+	PromptVersion string             `json:"promptVersion"`
+	CreatedAt     string             `json:"createdAt"`
+}
+
+// BulletSuggestion rewrites one bullet of an experience entry so it covers a
+// requirement of the posting. Original is copied from the resume, not from
+// the model, so Diff always applies to the text the user actually has.
+type BulletSuggestion struct {
+	ExperienceIndex int           `json:"experienceIndex"`
+	BulletIndex     int           `json:"bulletIndex"`
+	Company         string        `json:"company"`
+	Title           string        `json:"title"`
+	Original        string        `json:"original"`
+	Rewritten       string        `json:"rewritten"`
+	Addresses       string        `json:"addresses"`
+	Reason          string        `json:"reason"`
+	Diff            []DiffSegment `json:"diff"`
+	DiffText        string        `json:"diffText"`
+}
+
+// DiffSegment is a run of words that is unchanged, removed or added.
+type DiffSegment struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+const (
+	DiffEqual  string = "equal"
+	DiffDelete string = "delete"
+	DiffInsert string = "insert"
+)
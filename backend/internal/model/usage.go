@@ -0,0 +1,65 @@
+package model
+
+// UsageRecord is one LLM call. RequestedModel is set when the budget
+// downgraded the call to a cheaper Model. User is empty for calls made with
+// the server's own credentials.
+type UsageRecord struct {
+	Time             string  `json:"time"`
+	User             string  `json:"user,omitempty"`
+	Operation        string  `json:"operation"`
+	JobID            string  `json:"jobId,omitempty"`
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	RequestedModel   string  `json:"requestedModel,omitempty"`
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	LatencyMs        int64   `json:"latencyMs"`
+	Cost             float64 `json:"cost"`
+}
+
+// UsageReport totals LLM usage per day between From and To, inclusive.
+type UsageReport struct {
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	JobID    string       `json:"jobId,omitempty"`
+	Currency string       `json:"currency"`
+	Total    UsageTotals  `json:"total"`
+	Days     []UsageDay   `json:"days"`
+	Budget   *UsageBudget `json:"budget,omitempty"`
+}
+
+type UsageDay struct {
+	Date string `json:"date"`
+	UsageTotals
+	ByOperation map[string]UsageTotals `json:"byOperation"`
+	ByModel     map[string]UsageTotals `json:"byModel"`
+}
+
+type UsageTotals struct {
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	Cost             float64 `json:"cost"`
+	AvgLatencyMs     int64   `json:"avgLatencyMs"`
+}
+
+// UsageBudget is the spend against the monthly budget for the current
+// month.
+type UsageBudget struct {
+	Month     string  `json:"month"`
+	Limit     float64 `json:"limit"`
+	Spent     float64 `json:"spent"`
+	Remaining float64 `json:"remaining"`
+	Action    string  `json:"action"`
+	Exceeded  bool    `json:"exceeded"`
+}
+
+// RedactionEntry records what was redacted from one LLM request. Only the
+// kinds and counts are kept, never the values.
+type RedactionEntry struct {
+	Time      string         `json:"time"`
+	User      string         `json:"user,omitempty"`
+	Operation string         `json:"operation"`
+	JobID     string         `json:"jobId,omitempty"`
+	Counts    map[string]int `json:"counts"`
+}
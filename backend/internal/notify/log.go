@@ -0,0 +1,24 @@
+package notify
+
+import (
+	"job-parser-backend/internal/model"
+	"log"
+)
+
+type logNotifier struct{}
+
+// NewLogNotifier returns a notifier that writes notifications to the standard
+// logger. It needs no configuration, which makes it the default in
+// development and tests.
+func NewLogNotifier() Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Name() string {
+	return "log"
+}
+
+func (n *logNotifier) Notify(notification model.Notification) error {
+	log.Printf("Notification [%s] %s\n%s", notification.Kind, notification.Subject, notification.Text)
+	return nil
+}
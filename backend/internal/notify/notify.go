@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"fmt"
+	"job-parser-backend/internal/config"
+	"job-parser-backend/internal/model"
+	"net/http"
+	"strings"
+)
+
+// Notifier delivers a notification to a single destination.
+type Notifier interface {
+	Name() string
+	Notify(notification model.Notification) error
+}
+
+// CreateNotifiers builds the notifiers listed in cfg, of "log", "webhook"
+// and "smtp". The log notifier is used when the list is empty so reminders
+// are never silently dropped.
+func CreateNotifiers(httpClient *http.Client, cfg config.Notify) ([]Notifier, error) {
+	names := cfg.Notifiers
+	if len(names) == 0 {
+		names = []string{"log"}
+	}
+
+	var notifiers []Notifier
+	for _, name := range names {
+		switch strings.TrimSpace(name) {
+		case "log":
+			notifiers = append(notifiers, NewLogNotifier())
+		case "webhook":
+			notifier, err := CreateWebhookNotifier(httpClient, cfg.Webhook)
+			if err != nil {
+				return nil, fmt.Errorf("error creating webhook notifier: %w", err)
+			}
+			notifiers = append(notifiers, notifier)
+		case "smtp":
+			notifier, err := CreateSMTPNotifier(cfg.SMTP)
+			if err != nil {
+				return nil, fmt.Errorf("error creating SMTP notifier: %w", err)
+			}
+			notifiers = append(notifiers, notifier)
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown notifier %q", name)
+		}
+	}
+
+	return notifiers, nil
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/config"
+	"job-parser-backend/internal/model"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type smtpNotifier struct {
+	address string
+	auth    smtp.Auth
+	from    string
+	to      []string
+}
+
+// CreateSMTPNotifier sends notifications as email through the server in cfg.
+func CreateSMTPNotifier(cfg config.SMTP) (Notifier, error) {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("SMTP host, from and to must be set")
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &smtpNotifier{
+		address: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		auth:    auth,
+		from:    cfg.From,
+		to:      cfg.To,
+	}, nil
+}
+
+func (n *smtpNotifier) Name() string {
+	return "smtp"
+}
+
+func (n *smtpNotifier) Notify(notification model.Notification) error {
+	message, err := n.message(notification)
+	if err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(n.address, n.auth, n.from, n.to, message); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	return nil
+}
+
+// message builds an RFC 5322 email. Notifications with HTML are sent as
+// multipart/alternative so plain text clients still get a readable body.
+func (n *smtpNotifier) message(notification model.Notification) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	fmt.Fprintf(&buffer, "From: %s\r\n", n.from)
+	fmt.Fprintf(&buffer, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notification.Subject))
+	fmt.Fprintf(&buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+
+	if notification.HTML == "" {
+		buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		buffer.WriteString(notification.Text)
+		return buffer.Bytes(), nil
+	}
+
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("error generating MIME boundary: %w", err)
+	}
+	boundary := "job-parser-" + hex.EncodeToString(random)
+
+	fmt.Fprintf(&buffer, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+	fmt.Fprintf(&buffer, "--%s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n", boundary, notification.Text)
+	fmt.Fprintf(&buffer, "--%s\r\nContent-Type: text/html; charset=utf-8\r\n\r\n%s\r\n", boundary, notification.HTML)
+	fmt.Fprintf(&buffer, "--%s--\r\n", boundary)
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"job-parser-backend/internal/config"
+	"job-parser-backend/internal/model"
+	"net/http"
+)
+
+type webhookNotifier struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+// CreateWebhookNotifier posts notifications as JSON to cfg.URL. When
+// cfg.Secret is set it is sent as a bearer token.
+func CreateWebhookNotifier(httpClient *http.Client, cfg config.Webhook) (Notifier, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook URL is not set")
+	}
+
+	return &webhookNotifier{
+		url:        cfg.URL,
+		secret:     cfg.Secret,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (n *webhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *webhookNotifier) Notify(notification model.Notification) error {
+	bodyBytes, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("error marshalling notification: %w", err)
+	}
+
+	request, err := http.NewRequest("POST", n.url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("error creating HTTP request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		request.Header.Set("Authorization", "Bearer "+n.secret)
+	}
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error sending HTTP request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("webhook returned status code %d with body: %s", response.StatusCode, string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package prompt
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Prompt is a named template whose variables have type T, so callers cannot
+// render a prompt with the wrong data.
+type Prompt[T any] struct {
+	name string
+}
+
+type NoVars struct{}
+
+type CoverLetterVars struct {
+	Tone         string
+	Words        int
+	Instructions string
+}
+
+type ResumeSuggestionsVars struct {
+	MissingSkills []string
+}
+
+type InterviewPrepVars struct {
+	Title   string
+	Company string
+}
+
+var (
+	CompareJobPosting = Prompt[NoVars]{name: "compare_job_posting"}
+	ExtractJob        = Prompt[NoVars]{name: "extract_job"}
+	ParseResume       = Prompt[NoVars]{name: "parse_resume"}
+	CoverLetter       = Prompt[CoverLetterVars]{name: "cover_letter"}
+	ResumeSuggestions = Prompt[ResumeSuggestionsVars]{name: "resume_suggestions"}
+	InterviewPrep     = Prompt[InterviewPrepVars]{name: "interview_prep"}
+)
+
+// variables holds the zero value of every prompt's variables. Templates are
+// executed against it when loaded so a broken override fails at startup
+// rather than on the first request that uses it.
+var variables = map[string]any{
+	CompareJobPosting.name: NoVars{},
+	ExtractJob.name:        NoVars{},
+	ParseResume.name:       NoVars{},
+	CoverLetter.name:       CoverLetterVars{},
+	ResumeSuggestions.name: ResumeSuggestionsVars{},
+	InterviewPrep.name:     InterviewPrepVars{},
+}
+
+func (p Prompt[T]) Name() string {
+	return p.name
+}
+
+// Render executes the prompt from registry with vars.
+func (p Prompt[T]) Render(registry Registry, vars T) (Rendered, error) {
+	return registry.render(p.name, vars)
+}
+
+// Version returns the version the prompt would be rendered with, in the
+// same form as Rendered.Version.
+func (p Prompt[T]) Version(registry Registry) string {
+	return registry.version(p.name)
+}
+
+// Rendered is the text of a prompt together with the version it was rendered
+// from. Version is recorded with every LLM result.
+type Rendered struct {
+	Name    string
+	Version string
+	Text    string
+}
+
+// Info describes a loaded prompt.
+type Info struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Override bool   `json:"override"`
+}
+
+// Registry holds the prompt templates used for LLM calls. Each template file
+// starts with a {{/* version: N */}} header; the version must be bumped
+// whenever the prompt text changes so results can be traced to it.
+type Registry interface {
+	List() []Info
+	render(name string, vars any) (Rendered, error)
+	version(name string) string
+}
+
+type registry struct {
+	templates map[string]*entry
+}
+
+type entry struct {
+	info     Info
+	template *template.Template
+}
+
+var versionHeader = regexp.MustCompile(`^\{\{/\*\s*version:\s*(\S+)\s*\*/\}\}\r?\n`)
+
+// NewRegistry loads the embedded prompts and then any *.tmpl files in
+// overrideDir, which replace the embedded prompt of the same name. An empty
+// overrideDir uses the embedded prompts only.
+func NewRegistry(overrideDir string) (Registry, error) {
+	r := &registry{templates: make(map[string]*entry, len(variables))}
+
+	files, err := defaultTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("error reading embedded prompts: %w", err)
+	}
+	for _, file := range files {
+		data, err := defaultTemplates.ReadFile("templates/" + file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading embedded prompt %s: %w", file.Name(), err)
+		}
+		if err := r.load(file.Name(), data, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if overrideDir != "" {
+		paths, err := filepath.Glob(filepath.Join(overrideDir, "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("error listing prompt overrides: %w", err)
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("error reading prompt override: %w", err)
+			}
+			if err := r.load(filepath.Base(path), data, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for name := range variables {
+		if _, ok := r.templates[name]; !ok {
+			return nil, fmt.Errorf("prompt %s is missing", name)
+		}
+	}
+
+	return r, nil
+}
+
+func (r *registry) load(filename string, data []byte, override bool) error {
+	name := strings.TrimSuffix(filename, ".tmpl")
+	vars, ok := variables[name]
+	if !ok {
+		return fmt.Errorf("unknown prompt %s", filename)
+	}
+
+	match := versionHeader.FindSubmatch(data)
+	if match == nil {
+		return fmt.Errorf("prompt %s has no {{/* version: N */}} header", filename)
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(data[len(match[0]):]))
+	if err != nil {
+		return fmt.Errorf("error parsing prompt %s: %w", filename, err)
+	}
+
+	if err := tmpl.Execute(&bytes.Buffer{}, vars); err != nil {
+		return fmt.Errorf("error checking prompt %s: %w", filename, err)
+	}
+
+	r.templates[name] = &entry{
+		info: Info{
+			Name:     name,
+			Version:  string(match[1]),
+			Override: override,
+		},
+		template: tmpl,
+	}
+	return nil
+}
+
+// List returns the loaded prompts sorted by name.
+func (r *registry) List() []Info {
+	infos := make([]Info, 0, len(r.templates))
+	for _, entry := range r.templates {
+		infos = append(infos, entry.info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+func (r *registry) render(name string, vars any) (Rendered, error) {
+	entry, ok := r.templates[name]
+	if !ok {
+		return Rendered{}, errors.New("unknown prompt " + name)
+	}
+
+	var text bytes.Buffer
+	if err := entry.template.Execute(&text, vars); err != nil {
+		return Rendered{}, fmt.Errorf("error rendering prompt %s: %w", name, err)
+	}
+
+	return Rendered{
+		Name:    name,
+		Version: r.version(name),
+		Text:    strings.TrimSpace(text.String()),
+	}, nil
+}
+
+func (r *registry) version(name string) string {
+	entry, ok := r.templates[name]
+	if !ok {
+		return ""
+	}
+	return name + "@" + entry.info.Version
+}
@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket: Burst requests may be made at once, and the
+// bucket refills at Rate requests per second. The zero Limit allows every
+// request.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+var units = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// ParseLimit reads a limit written as count/unit, e.g. 10/m, with s, m or h
+// as the unit, or "off" for no limit. The count is also the burst.
+func ParseLimit(value string) (Limit, error) {
+	if value == "off" {
+		return Limit{}, nil
+	}
+	count, unit, ok := strings.Cut(strings.TrimSpace(value), "/")
+	n, err := strconv.Atoi(count)
+	if !ok || err != nil || n <= 0 || units[unit] == 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected e.g. 10/m or off", value)
+	}
+	return Limit{Rate: float64(n) / units[unit].Seconds(), Burst: n}, nil
+}
+
+// Limiter keeps one token bucket per key, such as an API key or IP.
+type Limiter interface {
+	// Allow takes a token from key's bucket, or returns false and how long
+	// until one is available.
+	Allow(key string) (bool, time.Duration)
+	// SetLimit changes the limit of every bucket. Buckets keep their
+	// tokens, up to the new burst.
+	SetLimit(limit Limit)
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type limiter struct {
+	// mu guards the limit, which can change while the server runs, and the
+	// buckets.
+	mu      sync.Mutex
+	limit   Limit
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// sweepInterval is how often buckets that have refilled are dropped, so
+// clients seen once do not hold memory.
+const sweepInterval = time.Minute
+
+func NewLimiter(limit Limit) Limiter {
+	return &limiter{limit: limit, buckets: make(map[string]*bucket)}
+}
+
+func (l *limiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit.Rate <= 0 {
+		return true, 0
+	}
+	if now.Sub(l.swept) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = min(float64(l.limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*l.limit.Rate)
+	b.updated = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.limit.Rate * float64(time.Second))
+}
+
+func (l *limiter) SetLimit(limit Limit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+	for _, b := range l.buckets {
+		b.tokens = min(b.tokens, float64(limit.Burst))
+	}
+}
+
+// sweep drops the buckets that would be full by now, which are the same as
+// no bucket.
+func (l *limiter) sweep(now time.Time) {
+	full := time.Duration(float64(l.limit.Burst) / l.limit.Rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= full {
+			delete(l.buckets, key)
+		}
+	}
+	l.swept = now
+}
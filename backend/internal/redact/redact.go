@@ -0,0 +1,268 @@
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/store"
+	"job-parser-backend/internal/tenant"
+	"job-parser-backend/internal/usage"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kinds of personal data that can be redacted. Name and location are only
+// known from the resume's contact fields; the others are also found by
+// pattern anywhere in the text.
+const (
+	KindEmail    = "email"
+	KindPhone    = "phone"
+	KindAddress  = "address"
+	KindLink     = "link"
+	KindName     = "name"
+	KindLocation = "location"
+)
+
+// DefaultKinds leaves the location alone, since comparisons weigh it
+// against the job's country.
+var DefaultKinds = []string{KindEmail, KindPhone, KindAddress, KindLink, KindName}
+
+var allKinds = []string{KindEmail, KindPhone, KindAddress, KindLink, KindName, KindLocation}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// phonePattern is loose; matches need minPhoneDigits digits, which
+	// rules out dates and year ranges.
+	phonePattern   = regexp.MustCompile(`\+?\(?\d[\d ().-]{7,}\d`)
+	addressPattern = regexp.MustCompile(`\b\d{1,5}\s+(?:[A-Z][A-Za-z0-9.'-]*\s+){1,4}(?:Street|St|Avenue|Ave|Road|Rd|Boulevard|Blvd|Lane|Ln|Drive|Dr|Court|Ct|Place|Pl|Way|Terrace|Parkway|Pkwy|Circle|Cir|Square|Sq)\b\.?(?:,?\s+(?:Apt|Suite|Unit|#)\.?\s*[A-Za-z0-9-]+)?` +
+		`|\b[A-ZÄÖÜ][a-zäöüß]+(?:straße|strasse|weg|gasse|platz|allee)\s+\d{1,4}[a-z]?\b`)
+	linkPattern = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?(?:linkedin\.com/in|github\.com|gitlab\.com|twitter\.com|x\.com)/[A-Za-z0-9_.-]+/?`)
+)
+
+const minPhoneDigits = 9
+
+// ParseKinds reads a comma-separated list of kinds. "none" disables
+// redaction and an empty value selects DefaultKinds.
+func ParseKinds(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultKinds, nil
+	}
+	if strings.TrimSpace(value) == "none" {
+		return nil, nil
+	}
+
+	var kinds []string
+	for _, kind := range strings.Split(value, ",") {
+		kind = strings.TrimSpace(kind)
+		if !slices.Contains(allKinds, kind) {
+			return nil, fmt.Errorf("unknown redaction kind %q, expected one of %s", kind, strings.Join(allKinds, ", "))
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+// Redactor replaces personal data with placeholders such as [EMAIL_1]
+// before text is sent to an LLM, and records what it replaced.
+type Redactor interface {
+	// Resume redacts the contact fields of resume and any personal data in
+	// the rest of it.
+	Resume(ctx context.Context, resume model.Resume) (model.Resume, *Redaction)
+	// Text redacts personal data found by pattern in free text, such as an
+	// uploaded resume before it is parsed.
+	Text(ctx context.Context, text string) (string, *Redaction)
+	// Audit lists what was redacted for the user of ctx between from and
+	// to, inclusive.
+	Audit(ctx context.Context, from time.Time, to time.Time) ([]model.RedactionEntry, error)
+}
+
+type redactor struct {
+	kinds   []string
+	records store.Log
+}
+
+// NewRedactor redacts kinds and appends the audit entries to records, which
+// may be nil to keep none. With no kinds nothing is redacted.
+func NewRedactor(kinds []string, records store.Log) Redactor {
+	return &redactor{kinds: kinds, records: records}
+}
+
+func (r *redactor) Resume(ctx context.Context, resume model.Resume) (model.Resume, *Redaction) {
+	redaction := newRedaction()
+
+	// Contact fields are replaced first and by value, so the same name or
+	// email elsewhere in the resume gets the same placeholder.
+	contact := &resume.Contact
+	if r.enabled(KindName) {
+		contact.Name = redaction.replace(KindName, contact.Name)
+	}
+	if r.enabled(KindEmail) {
+		contact.Email = redaction.replace(KindEmail, contact.Email)
+	}
+	if r.enabled(KindPhone) {
+		contact.Phone = redaction.replace(KindPhone, contact.Phone)
+	}
+	if r.enabled(KindLocation) {
+		contact.Location = redaction.replace(KindLocation, contact.Location)
+	}
+	if r.enabled(KindLink) {
+		contact.Links = slices.Clone(contact.Links)
+		for i, link := range contact.Links {
+			contact.Links[i] = redaction.replace(KindLink, link)
+		}
+	}
+
+	if redaction.empty() && len(r.kinds) == 0 {
+		return resume, redaction
+	}
+
+	// Everything else goes through the text patterns, via JSON so every
+	// string field is covered.
+	data, err := json.Marshal(resume)
+	if err != nil {
+		return resume, redaction
+	}
+	var redacted model.Resume
+	if err := json.Unmarshal([]byte(r.text(redaction, string(data))), &redacted); err != nil {
+		log.Printf("Failed to redact resume text: %v", err)
+		redacted = resume
+	}
+
+	r.audit(ctx, redaction)
+	return redacted, redaction
+}
+
+func (r *redactor) Text(ctx context.Context, text string) (string, *Redaction) {
+	redaction := newRedaction()
+	text = r.text(redaction, text)
+	r.audit(ctx, redaction)
+	return text, redaction
+}
+
+// text replaces the values already known to redaction wherever they occur,
+// then the patterns of the enabled kinds.
+func (r *redactor) text(redaction *Redaction, text string) string {
+	for _, original := range redaction.originals() {
+		text = replaceWhole(text, original, redaction.byValue[original])
+		// Resumes are redacted as JSON, where quotes and backslashes in a
+		// value are escaped.
+		if escaped, err := json.Marshal(original); err == nil && string(escaped[1:len(escaped)-1]) != original {
+			text = replaceWhole(text, string(escaped[1:len(escaped)-1]), redaction.byValue[original])
+		}
+	}
+
+	if r.enabled(KindEmail) {
+		text = emailPattern.ReplaceAllStringFunc(text, func(match string) string {
+			return redaction.replace(KindEmail, match)
+		})
+	}
+	if r.enabled(KindLink) {
+		text = linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+			return redaction.replace(KindLink, match)
+		})
+	}
+	if r.enabled(KindAddress) {
+		text = addressPattern.ReplaceAllStringFunc(text, func(match string) string {
+			return redaction.replace(KindAddress, match)
+		})
+	}
+	if r.enabled(KindPhone) {
+		text = phonePattern.ReplaceAllStringFunc(text, func(match string) string {
+			if digits(match) < minPhoneDigits {
+				return match
+			}
+			return redaction.replace(KindPhone, match)
+		})
+	}
+	return text
+}
+
+func (r *redactor) enabled(kind string) bool {
+	return slices.Contains(r.kinds, kind)
+}
+
+// audit records the kinds and counts of a redaction, labelled with the
+// operation and job of ctx. Failing to record it does not fail the request.
+func (r *redactor) audit(ctx context.Context, redaction *Redaction) {
+	if redaction.empty() || r.records == nil {
+		return
+	}
+
+	now := time.Now()
+	entry := model.RedactionEntry{
+		Time:      now.Format(time.RFC3339),
+		User:      tenant.Key(ctx),
+		Operation: usage.Operation(ctx),
+		JobID:     usage.Job(ctx),
+		Counts:    redaction.Counts(),
+	}
+	if err := r.records.Append(now, entry); err != nil {
+		log.Printf("Failed to record redaction audit: %v", err)
+	}
+}
+
+func (r *redactor) Audit(ctx context.Context, from time.Time, to time.Time) ([]model.RedactionEntry, error) {
+	user := tenant.Key(ctx)
+	entries := []model.RedactionEntry{}
+	if r.records == nil {
+		return entries, nil
+	}
+
+	err := r.records.Read(from, to, func(date string, raw json.RawMessage) error {
+		var entry model.RedactionEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return fmt.Errorf("error decoding redaction audit for %s: %w", date, err)
+		}
+		if entry.User == user {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading redaction audit: %w", err)
+	}
+	return entries, nil
+}
+
+// replaceWhole replaces value where it is not part of a longer word, so a
+// short name does not match inside other words.
+func replaceWhole(text string, value string, replacement string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(text, value)
+		if i < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		end := i + len(value)
+		before, _ := utf8.DecodeLastRuneInString(text[:i])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		b.WriteString(text[:i])
+		if isWordRune(before) || isWordRune(after) {
+			b.WriteString(value)
+		} else {
+			b.WriteString(replacement)
+		}
+		text = text[end:]
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func digits(s string) int {
+	count := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			count++
+		}
+	}
+	return count
+}
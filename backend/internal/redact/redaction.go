@@ -0,0 +1,103 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Redaction maps the placeholders of one request back to the values they
+// replaced. It never leaves the server.
+type Redaction struct {
+	byPlaceholder map[string]string
+	byValue       map[string]string
+	counts        map[string]int
+}
+
+func newRedaction() *Redaction {
+	return &Redaction{
+		byPlaceholder: map[string]string{},
+		byValue:       map[string]string{},
+		counts:        map[string]int{},
+	}
+}
+
+// replace returns the placeholder for value, numbering a new one per kind.
+// Repeated values share a placeholder.
+func (r *Redaction) replace(kind string, value string) string {
+	if strings.TrimSpace(value) == "" {
+		return value
+	}
+	if placeholder, ok := r.byValue[value]; ok {
+		return placeholder
+	}
+
+	r.counts[kind]++
+	placeholder := fmt.Sprintf("[%s_%d]", strings.ToUpper(kind), r.counts[kind])
+	r.byPlaceholder[placeholder] = value
+	r.byValue[value] = placeholder
+	return placeholder
+}
+
+// originals returns the redacted values, longest first so a value that
+// contains another is replaced whole.
+func (r *Redaction) originals() []string {
+	values := make([]string, 0, len(r.byValue))
+	for value := range r.byValue {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	return values
+}
+
+func (r *Redaction) empty() bool {
+	return r == nil || len(r.byPlaceholder) == 0
+}
+
+// Counts returns how many distinct values of each kind were redacted.
+func (r *Redaction) Counts() map[string]int {
+	counts := make(map[string]int, len(r.counts))
+	for kind, count := range r.counts {
+		counts[kind] = count
+	}
+	return counts
+}
+
+// Restore puts the original values back in text.
+func (r *Redaction) Restore(text string) string {
+	if r.empty() {
+		return text
+	}
+	for placeholder, value := range r.byPlaceholder {
+		text = strings.ReplaceAll(text, placeholder, value)
+	}
+	return text
+}
+
+// RestoreJSON puts the original values back in every string of out, a
+// pointer to a decoded model reply.
+func (r *Redaction) RestoreJSON(out any) error {
+	if r.empty() {
+		return nil
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return fmt.Errorf("error encoding reply to restore: %w", err)
+	}
+
+	text := string(data)
+	for placeholder, value := range r.byPlaceholder {
+		quoted, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("error encoding redacted value: %w", err)
+		}
+		text = strings.ReplaceAll(text, placeholder, string(quoted[1:len(quoted)-1]))
+	}
+
+	if err := json.Unmarshal([]byte(text), out); err != nil {
+		return fmt.Errorf("error decoding restored reply: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"job-parser-backend/internal/model"
+	"sort"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*
+var templateFiles embed.FS
+
+const (
+	FormatHTML     string = "html"
+	FormatMarkdown string = "markdown"
+)
+
+var funcs = map[string]any{
+	"date":   formatDate,
+	"days":   sortedDays,
+	"status": sortedStatuses,
+}
+
+var (
+	htmlTemplates     = htmltemplate.Must(htmltemplate.New("").Funcs(funcs).ParseFS(templateFiles, "templates/*.html.tmpl"))
+	markdownTemplates = texttemplate.Must(texttemplate.New("").Funcs(funcs).ParseFS(templateFiles, "templates/*.md.tmpl"))
+)
+
+// RenderWeekly renders report as HTML or Markdown.
+func RenderWeekly(report *model.WeeklyReport, format string) (string, error) {
+	var buffer bytes.Buffer
+	var err error
+
+	switch format {
+	case FormatHTML:
+		err = htmlTemplates.ExecuteTemplate(&buffer, "weekly.html.tmpl", report)
+	case FormatMarkdown:
+		err = markdownTemplates.ExecuteTemplate(&buffer, "weekly.md.tmpl", report)
+	default:
+		return "", fmt.Errorf("unsupported report format %q", format)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("error rendering weekly report: %w", err)
+	}
+
+	return buffer.String(), nil
+}
+
+// formatDate shortens RFC 3339 timestamps to "Mon Jan 2" for display.
+func formatDate(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Format("Mon Jan 2")
+}
+
+type dayCount struct {
+	Day   string
+	Count int
+}
+
+func sortedDays(counts map[string]int) []dayCount {
+	days := make([]dayCount, 0, len(counts))
+	for day, count := range counts {
+		days = append(days, dayCount{Day: day, Count: count})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day < days[j].Day })
+	return days
+}
+
+type statusCount struct {
+	Status string
+	Count  int
+}
+
+func sortedStatuses(counts map[string]int) []statusCount {
+	statuses := make([]statusCount, 0, len(counts))
+	for status, count := range counts {
+		statuses = append(statuses, statusCount{Status: status, Count: count})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Count != statuses[j].Count {
+			return statuses[i].Count > statuses[j].Count
+		}
+		return statuses[i].Status < statuses[j].Status
+	})
+	return statuses
+}
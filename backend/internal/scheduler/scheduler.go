@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Scheduler runs background tasks on fixed intervals inside the server
+// process.
+type Scheduler interface {
+	Every(name string, interval time.Duration, task func() error)
+	Start(ctx context.Context)
+	Stop()
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	task     func() error
+}
+
+type scheduler struct {
+	jobs   []job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler() Scheduler {
+	return &scheduler{}
+}
+
+// Every registers task to run once at start-up and then every interval.
+// Tasks must be registered before Start is called.
+func (s *scheduler) Every(name string, interval time.Duration, task func() error) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, task: task})
+}
+
+func (s *scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go func(j job) {
+			defer s.wg.Done()
+			s.run(ctx, j)
+		}(j)
+	}
+}
+
+// Stop cancels all tasks and waits for any run in progress to finish.
+func (s *scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *scheduler) run(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.task(); err != nil {
+			log.Printf("Scheduled task %s failed: %v", j.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/config"
+	"os"
+	"strings"
+)
+
+// masterKey encrypts the data keys of secrets. Its ID is derived from the
+// key, so rotated keys need no naming.
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+func parseMasterKey(encoded string) (*masterKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("master keys must be 32 bytes, base64 encoded")
+	}
+
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(raw)
+	return &masterKey{id: hex.EncodeToString(sum[:4]), aead: aead}, nil
+}
+
+// readMasterKeys reads the master keys from cfg.MasterKeyFile, one per line
+// with the current key first, or else from cfg.MasterKey and
+// cfg.PreviousMasterKeys. It returns nil when none is set.
+func readMasterKeys(cfg config.Secrets) ([]string, error) {
+	var encoded []string
+	if path := cfg.MasterKeyFile; path != "" {
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading master key file: %w", err)
+		}
+		encoded = strings.Fields(string(bytes))
+		if len(encoded) == 0 {
+			return nil, fmt.Errorf("master key file %s is empty", path)
+		}
+	} else if cfg.MasterKey != "" {
+		encoded = append([]string{cfg.MasterKey}, cfg.PreviousMasterKeys...)
+	}
+	return encoded, nil
+}
+
+// GenerateKey returns a new random key, base64 encoded, for use as a master
+// key.
+func GenerateKey() string {
+	return base64.StdEncoding.EncodeToString(randomBytes(32))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// seal encrypts plaintext with a random nonce, which it puts first.
+// additional binds the result to where it is stored.
+func seal(aead cipher.AEAD, plaintext []byte, additional []byte) []byte {
+	nonce := randomBytes(aead.NonceSize())
+	return aead.Seal(nonce, nonce, plaintext, additional)
+}
+
+func open(aead cipher.AEAD, sealed []byte, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrCorrupt
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	return plaintext, nil
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic("crypto/rand failed: " + err.Error())
+	}
+	return b
+}
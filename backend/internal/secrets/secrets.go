@@ -0,0 +1,306 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/config"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("secret not found")
+	ErrNoMasterKey = errors.New("no secrets master key configured")
+	ErrCorrupt     = errors.New("secret cannot be decrypted")
+)
+
+// Store keeps secrets such as API keys encrypted at rest. Each secret is
+// encrypted with its own data key, and the data key with the master key
+// (envelope encryption), so rotating the master key only re-encrypts the
+// small data keys.
+type Store interface {
+	Get(name string) (string, error)
+	Put(name string, value string) error
+	Delete(name string) error
+	List() ([]Info, error)
+	// Lookup returns the secret stored under name, or else fallback, the
+	// value from the configuration, so deployments that configure keys in
+	// the environment or a config file keep working.
+	Lookup(name string, fallback string) (string, error)
+	// Rotate re-encrypts every data key with the current master key and
+	// returns how many were still under an older one.
+	Rotate() (int, error)
+	// Writable reports whether a master key is configured, without which
+	// nothing can be stored.
+	Writable() bool
+}
+
+// Info describes a stored secret without revealing it.
+type Info struct {
+	Name        string `json:"name"`
+	MasterKeyID string `json:"masterKeyId"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+// envelope is a secret as stored: the value sealed with a data key, and the
+// data key sealed with the master key named by MasterKeyID.
+type envelope struct {
+	MasterKeyID string `json:"masterKeyId"`
+	DataKey     []byte `json:"dataKey"`
+	Value       []byte `json:"value"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+type fileStore struct {
+	path string
+	// keys are the master keys, the current one first.
+	keys []*masterKey
+
+	// mu guards the envelopes read from path, which are reloaded when
+	// another process, such as the secrets CLI, changes the file.
+	mu        sync.Mutex
+	modTime   time.Time
+	envelopes map[string]envelope
+}
+
+// CreateStore opens the secrets file at cfg.Path with the master keys of
+// cfg. Without a master key, Lookup still returns the configured values but
+// nothing can be stored.
+func CreateStore(cfg config.Secrets) (Store, error) {
+	keys, err := readMasterKeys(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStore(cfg.Path, keys)
+}
+
+// NewStore opens the secrets file at path. masterKeys are base64-encoded
+// 32-byte keys, the current one first; the others only decrypt secrets not
+// yet rotated.
+func NewStore(path string, masterKeys []string) (Store, error) {
+	s := &fileStore{path: path}
+	for _, encoded := range masterKeys {
+		if strings.TrimSpace(encoded) == "" {
+			continue
+		}
+		key, err := parseMasterKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		s.keys = append(s.keys, key)
+	}
+	return s, nil
+}
+
+func (s *fileStore) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return "", err
+	}
+	sealed, ok := s.envelopes[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return s.open(name, sealed)
+}
+
+func (s *fileStore) Lookup(name string, fallback string) (string, error) {
+	value, err := s.Get(name)
+	if errors.Is(err, ErrNotFound) {
+		return fallback, nil
+	}
+	return value, err
+}
+
+func (s *fileStore) Put(name string, value string) error {
+	if len(s.keys) == 0 {
+		return ErrNoMasterKey
+	}
+
+	dataKey := randomBytes(32)
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	sealed := envelope{
+		MasterKeyID: s.keys[0].id,
+		DataKey:     seal(s.keys[0].aead, dataKey, []byte(name)),
+		Value:       seal(aead, []byte(value), []byte(name)),
+		UpdatedAt:   time.Now().Format(time.RFC3339),
+	}
+
+	return s.update(func(envelopes map[string]envelope) error {
+		envelopes[name] = sealed
+		return nil
+	})
+}
+
+func (s *fileStore) Delete(name string) error {
+	return s.update(func(envelopes map[string]envelope) error {
+		if _, ok := envelopes[name]; !ok {
+			return ErrNotFound
+		}
+		delete(envelopes, name)
+		return nil
+	})
+}
+
+func (s *fileStore) List() ([]Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	infos := make([]Info, 0, len(s.envelopes))
+	for name, sealed := range s.envelopes {
+		infos = append(infos, Info{Name: name, MasterKeyID: sealed.MasterKeyID, UpdatedAt: sealed.UpdatedAt})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+func (s *fileStore) Rotate() (int, error) {
+	if len(s.keys) == 0 {
+		return 0, ErrNoMasterKey
+	}
+	current := s.keys[0]
+
+	rotated := 0
+	err := s.update(func(envelopes map[string]envelope) error {
+		for name, sealed := range envelopes {
+			if sealed.MasterKeyID == current.id {
+				continue
+			}
+			dataKey, err := s.openDataKey(name, sealed)
+			if err != nil {
+				return fmt.Errorf("error rotating %s: %w", name, err)
+			}
+			sealed.MasterKeyID = current.id
+			sealed.DataKey = seal(current.aead, dataKey, []byte(name))
+			envelopes[name] = sealed
+			rotated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rotated, nil
+}
+
+func (s *fileStore) Writable() bool {
+	return len(s.keys) > 0
+}
+
+func (s *fileStore) open(name string, sealed envelope) (string, error) {
+	dataKey, err := s.openDataKey(name, sealed)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting %s: %w", name, err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	value, err := open(aead, sealed.Value, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("error decrypting %s: %w", name, err)
+	}
+	return string(value), nil
+}
+
+func (s *fileStore) openDataKey(name string, sealed envelope) ([]byte, error) {
+	for _, key := range s.keys {
+		if key.id == sealed.MasterKeyID {
+			return open(key.aead, sealed.DataKey, []byte(name))
+		}
+	}
+	return nil, fmt.Errorf("%w: master key %s is not configured", ErrNoMasterKey, sealed.MasterKeyID)
+}
+
+// reload rereads the file when it changed since it was last read. It must
+// be called with mu held.
+func (s *fileStore) reload() error {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.envelopes, s.modTime = map[string]envelope{}, time.Time{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading secrets: %w", err)
+	}
+	if s.envelopes != nil && info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+
+	envelopes, err := s.read()
+	if err != nil {
+		return err
+	}
+	s.envelopes, s.modTime = envelopes, info.ModTime()
+	return nil
+}
+
+func (s *fileStore) read() (map[string]envelope, error) {
+	envelopes := make(map[string]envelope)
+
+	bytes, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return envelopes, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading secrets: %w", err)
+	}
+
+	if len(bytes) > 0 {
+		if err := json.Unmarshal(bytes, &envelopes); err != nil {
+			return nil, fmt.Errorf("error decoding secrets: %w", err)
+		}
+	}
+	return envelopes, nil
+}
+
+// update rewrites the file with the result of fn on its current contents,
+// replacing it atomically so readers never see a partial file.
+func (s *fileStore) update(fn func(envelopes map[string]envelope) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	envelopes, err := s.read()
+	if err != nil {
+		return err
+	}
+	if err := fn(envelopes); err != nil {
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(envelopes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding secrets: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("error creating secrets directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, bytes, 0o600); err != nil {
+		return fmt.Errorf("error writing secrets: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("error replacing secrets: %w", err)
+	}
+
+	s.envelopes = nil
+	return nil
+}
@@ -0,0 +1,240 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	aead, err := newAEAD(randomBytes(32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		plaintext  string
+		additional string
+		tamper     func(sealed []byte) []byte
+		openWith   string
+		wantErr    error
+	}{
+		{name: "round trip", plaintext: "secret_value", additional: "NOTION_API_KEY", openWith: "NOTION_API_KEY"},
+		{name: "empty value", plaintext: "", additional: "EMPTY", openWith: "EMPTY"},
+		{name: "unicode value", plaintext: "pässwörd ✓", additional: "UNICODE", openWith: "UNICODE"},
+		{name: "other additional data", plaintext: "secret_value", additional: "NOTION_API_KEY", openWith: "GROQ_API_KEY", wantErr: ErrCorrupt},
+		{
+			name: "flipped byte", plaintext: "secret_value", additional: "NOTION_API_KEY", openWith: "NOTION_API_KEY", wantErr: ErrCorrupt,
+			tamper: func(sealed []byte) []byte { sealed[len(sealed)-1] ^= 1; return sealed },
+		},
+		{
+			name: "truncated", plaintext: "secret_value", additional: "NOTION_API_KEY", openWith: "NOTION_API_KEY", wantErr: ErrCorrupt,
+			tamper: func(sealed []byte) []byte { return sealed[:aead.NonceSize()-1] },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sealed := seal(aead, []byte(test.plaintext), []byte(test.additional))
+			if test.tamper != nil {
+				sealed = test.tamper(sealed)
+			}
+
+			plaintext, err := open(aead, sealed, []byte(test.openWith))
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("open() error = %v, want %v", err, test.wantErr)
+			}
+			if err == nil && string(plaintext) != test.plaintext {
+				t.Errorf("open() = %q, want %q", plaintext, test.plaintext)
+			}
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	key := GenerateKey()
+
+	tests := []struct {
+		name    string
+		keys    []string
+		put     map[string]string
+		get     string
+		want    string
+		wantErr error
+	}{
+		{name: "round trip", keys: []string{key}, put: map[string]string{"NOTION_API_KEY": "notion"}, get: "NOTION_API_KEY", want: "notion"},
+		{name: "other secrets kept", keys: []string{key}, put: map[string]string{"NOTION_API_KEY": "notion", "GROQ_API_KEY": "groq"}, get: "GROQ_API_KEY", want: "groq"},
+		{name: "missing secret", keys: []string{key}, put: map[string]string{"NOTION_API_KEY": "notion"}, get: "GROQ_API_KEY", wantErr: ErrNotFound},
+		{name: "no master key", put: map[string]string{"NOTION_API_KEY": "notion"}, wantErr: ErrNoMasterKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, err := NewStore(filepath.Join(t.TempDir(), "secrets.json"), test.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, value := range test.put {
+				if err = store.Put(name, value); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				var value string
+				value, err = store.Get(test.get)
+				if err == nil && value != test.want {
+					t.Errorf("Get(%q) = %q, want %q", test.get, value, test.want)
+				}
+			}
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// TestSwappedEnvelopes checks that an envelope moved to another name in the
+// file does not decrypt, since the name is bound to it as additional data.
+func TestSwappedEnvelopes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	keys := []string{GenerateKey()}
+	store, err := NewStore(path, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("NOTION_API_KEY", "notion"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("GROQ_API_KEY", "groq"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		swap func(envelopes map[string]envelope)
+	}{
+		{
+			name: "whole envelopes",
+			swap: func(envelopes map[string]envelope) {
+				envelopes["NOTION_API_KEY"], envelopes["GROQ_API_KEY"] = envelopes["GROQ_API_KEY"], envelopes["NOTION_API_KEY"]
+			},
+		},
+		{
+			name: "data keys",
+			swap: func(envelopes map[string]envelope) {
+				notion, groq := envelopes["NOTION_API_KEY"], envelopes["GROQ_API_KEY"]
+				notion.DataKey, groq.DataKey = groq.DataKey, notion.DataKey
+				envelopes["NOTION_API_KEY"], envelopes["GROQ_API_KEY"] = notion, groq
+			},
+		},
+		{
+			name: "values",
+			swap: func(envelopes map[string]envelope) {
+				notion, groq := envelopes["NOTION_API_KEY"], envelopes["GROQ_API_KEY"]
+				notion.Value, groq.Value = groq.Value, notion.Value
+				envelopes["NOTION_API_KEY"], envelopes["GROQ_API_KEY"] = notion, groq
+			},
+		},
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var envelopes map[string]envelope
+			if err := json.Unmarshal(original, &envelopes); err != nil {
+				t.Fatal(err)
+			}
+			test.swap(envelopes)
+			swapped := filepath.Join(t.TempDir(), "secrets.json")
+			writeEnvelopes(t, swapped, envelopes)
+
+			store, err := NewStore(swapped, keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range []string{"NOTION_API_KEY", "GROQ_API_KEY"} {
+				if value, err := store.Get(name); !errors.Is(err, ErrCorrupt) {
+					t.Errorf("Get(%q) = %q, %v, want %v", name, value, err, ErrCorrupt)
+				}
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	previous, current := GenerateKey(), GenerateKey()
+
+	tests := []struct {
+		name        string
+		writtenWith []string
+		rotateWith  []string
+		wantRotated int
+		wantErr     error
+	}{
+		{name: "previous key", writtenWith: []string{previous}, rotateWith: []string{current, previous}, wantRotated: 2},
+		{name: "already current", writtenWith: []string{current}, rotateWith: []string{current, previous}, wantRotated: 0},
+		{name: "previous key missing", writtenWith: []string{previous}, rotateWith: []string{current}, wantErr: ErrNoMasterKey},
+		{name: "no master key", writtenWith: []string{previous}, wantErr: ErrNoMasterKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "secrets.json")
+			secrets := map[string]string{"NOTION_API_KEY": "notion", "GROQ_API_KEY": "groq"}
+
+			writer, err := NewStore(path, test.writtenWith)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, value := range secrets {
+				if err := writer.Put(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			rotator, err := NewStore(path, test.rotateWith)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rotated, err := rotator.Rotate()
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Rotate() error = %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if rotated != test.wantRotated {
+				t.Errorf("Rotate() = %d, want %d", rotated, test.wantRotated)
+			}
+
+			// Once rotated, the secrets no longer need the previous key.
+			reader, err := NewStore(path, []string{current})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range secrets {
+				if value, err := reader.Get(name); err != nil || value != want {
+					t.Errorf("Get(%q) = %q, %v, want %q", name, value, err, want)
+				}
+			}
+		})
+	}
+}
+
+func writeEnvelopes(t *testing.T, path string, envelopes map[string]envelope) {
+	t.Helper()
+	bytes, err := json.Marshal(envelopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/client"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/redact"
+	"job-parser-backend/internal/store"
+	"job-parser-backend/internal/usage"
+	"job-parser-backend/internal/utils"
+	"time"
+)
+
+var (
+	ErrCoverLetterNotFound = errors.New("cover letter not found")
+	ErrInvalidLength       = errors.New("length must be short, medium or long")
+)
+
+type CoverLetterService interface {
+	GenerateCoverLetter(ctx context.Context, jobID string, request model.CoverLetterRequest) (*model.CoverLetter, error)
+	GetCoverLetters(ctx context.Context, jobID string) ([]model.CoverLetter, error)
+	GetCoverLetter(ctx context.Context, jobID string, version int) (*model.CoverLetter, error)
+	GetSettings(ctx context.Context) (*model.CoverLetterSettings, error)
+	UpdateSettings(ctx context.Context, settings model.CoverLetterSettings) (*model.CoverLetterSettings, error)
+}
+
+type coverLetterService struct {
+	llmProvider   client.LLMProvider
+	store         store.Store
+	prompts       prompt.Registry
+	redactor      redact.Redactor
+	jobService    JobService
+	resumeService ResumeService
+}
+
+const (
+	coverLetterCollection = "cover_letters"
+	settingsCollection    = "settings"
+	coverLetterSettingKey = "cover_letter"
+)
+
+// coverLetterWords is the target length in words for each length setting.
+var coverLetterWords = map[string]int{
+	model.CoverLetterShort:  150,
+	model.CoverLetterMedium: 250,
+	model.CoverLetterLong:   400,
+}
+
+var defaultCoverLetterSettings = model.CoverLetterSettings{
+	Tone:   "professional and warm",
+	Length: model.CoverLetterMedium,
+}
+
+func NewCoverLetterService(llmProvider client.LLMProvider, store store.Store, prompts prompt.Registry, redactor redact.Redactor, jobService JobService, resumeService ResumeService) CoverLetterService {
+	return &coverLetterService{
+		llmProvider:   llmProvider,
+		store:         store,
+		prompts:       prompts,
+		redactor:      redactor,
+		jobService:    jobService,
+		resumeService: resumeService,
+	}
+}
+
+// GenerateCoverLetter writes a cover letter for a saved job from its stored
+// description and the selected resume, and saves it as the job's next draft.
+func (s *coverLetterService) GenerateCoverLetter(ctx context.Context, jobID string, request model.CoverLetterRequest) (*model.CoverLetter, error) {
+	ctx = usage.WithJob(ctx, jobID)
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if request.Tone != "" {
+		settings.Tone = request.Tone
+	}
+	if request.Length != "" {
+		settings.Length = request.Length
+	}
+	if request.Instructions != "" {
+		settings.Instructions = request.Instructions
+	}
+
+	words, ok := coverLetterWords[settings.Length]
+	if !ok {
+		return nil, ErrInvalidLength
+	}
+
+	job, err := s.jobService.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Description == "" {
+		return nil, ErrNoJobPosting
+	}
+
+	resume, err := s.resumeService.ResolveResume(ctx, model.CompareRequest{
+		ResumeID:      request.ResumeID,
+		ResumeVersion: request.ResumeVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	systemPrompt, err := prompt.CoverLetter.Render(s.prompts, prompt.CoverLetterVars{
+		Tone:         settings.Tone,
+		Words:        words,
+		Instructions: settings.Instructions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx = usage.WithOperation(ctx, systemPrompt.Name)
+
+	redacted, redaction := redactResume(ctx, s.redactor, resume.Resume)
+	bytes, err := json.Marshal(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling resume: %w", err)
+	}
+
+	messages := []map[string]string{
+		{
+			"role":    "system",
+			"content": systemPrompt.Text,
+		},
+		{
+			"role":    "user",
+			"content": "Resume:\n" + string(bytes),
+		},
+		{
+			"role":    "user",
+			"content": "Job Posting:\n" + utils.DelimitPosting(fmt.Sprintf("%s at %s\n%s", job.Title, job.Company, job.Description)),
+		},
+	}
+
+	var response struct {
+		CoverLetter string `json:"coverLetter"`
+	}
+	answeredBy, err := chatJSON(ctx, s.llmProvider, model.Gemma2_9B_Instruct, messages, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error generating cover letter: %w", err)
+	}
+	if err := redaction.RestoreJSON(&response); err != nil {
+		return nil, fmt.Errorf("error generating cover letter: %w", err)
+	}
+	if response.CoverLetter == "" {
+		return nil, errors.New("error generating cover letter: model returned an empty letter")
+	}
+
+	letter := model.CoverLetter{
+		JobID:         jobID,
+		ResumeID:      resume.ResumeID,
+		ResumeVersion: resume.Version,
+		Tone:          settings.Tone,
+		Length:        settings.Length,
+		Content:       response.CoverLetter,
+		Model:         answeredBy,
+		PromptVersion: systemPrompt.Version,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+	}
+
+	err = s.store.Update(userCollection(ctx, coverLetterCollection), jobID, func(current json.RawMessage) (any, error) {
+		var letters []model.CoverLetter
+		if current != nil {
+			if err := json.Unmarshal(current, &letters); err != nil {
+				return nil, fmt.Errorf("error decoding cover letters: %w", err)
+			}
+		}
+		letter.Version = len(letters) + 1
+		return append(letters, letter), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error saving cover letter: %w", err)
+	}
+
+	return &letter, nil
+}
+
+func (s *coverLetterService) GetCoverLetters(ctx context.Context, jobID string) ([]model.CoverLetter, error) {
+	return coverLetters(ctx, s.store, jobID)
+}
+
+// GetCoverLetter returns one draft, or the latest when version is zero.
+func (s *coverLetterService) GetCoverLetter(ctx context.Context, jobID string, version int) (*model.CoverLetter, error) {
+	letters, err := s.GetCoverLetters(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == 0 {
+		version = len(letters)
+	}
+	if version < 1 || version > len(letters) {
+		return nil, ErrCoverLetterNotFound
+	}
+
+	return &letters[version-1], nil
+}
+
+func (s *coverLetterService) GetSettings(ctx context.Context) (*model.CoverLetterSettings, error) {
+	settings := defaultCoverLetterSettings
+	err := s.store.Get(userCollection(ctx, settingsCollection), coverLetterSettingKey, &settings)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("error reading cover letter settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (s *coverLetterService) UpdateSettings(ctx context.Context, settings model.CoverLetterSettings) (*model.CoverLetterSettings, error) {
+	if settings.Tone == "" {
+		settings.Tone = defaultCoverLetterSettings.Tone
+	}
+	if settings.Length == "" {
+		settings.Length = defaultCoverLetterSettings.Length
+	}
+	if _, ok := coverLetterWords[settings.Length]; !ok {
+		return nil, ErrInvalidLength
+	}
+
+	if err := s.store.Put(userCollection(ctx, settingsCollection), coverLetterSettingKey, settings); err != nil {
+		return nil, fmt.Errorf("error saving cover letter settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func coverLetters(ctx context.Context, jobStore store.Store, jobID string) ([]model.CoverLetter, error) {
+	var letters []model.CoverLetter
+	err := jobStore.Get(userCollection(ctx, coverLetterCollection), jobID, &letters)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("error reading cover letters: %w", err)
+	}
+	return letters, nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/client"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/redact"
+	"job-parser-backend/internal/store"
+	"job-parser-backend/internal/usage"
+	"job-parser-backend/internal/utils"
+	"log"
+	"time"
+)
+
+var ErrInterviewPrepNotFound = errors.New("interview prep not found")
+
+// interviewStatus is the Notion status that triggers automatic prep packs.
+const interviewStatus = "Interview"
+
+type InterviewPrepService interface {
+	GenerateInterviewPrep(ctx context.Context, jobID string, request model.CompareRequest) (*model.InterviewPrep, error)
+	GetInterviewPrep(ctx context.Context, jobID string) (*model.InterviewPrep, error)
+	HandleStatusChange(ctx context.Context, jobID string, status string)
+}
+
+type interviewPrepService struct {
+	llmProvider   client.LLMProvider
+	store         store.Store
+	prompts       prompt.Registry
+	redactor      redact.Redactor
+	jobService    JobService
+	resumeService ResumeService
+}
+
+const interviewPrepCollection = "interview_prep"
+
+// maxPromptField bounds the posting fields put into the system prompt.
+const maxPromptField = 120
+
+func NewInterviewPrepService(llmProvider client.LLMProvider, store store.Store, prompts prompt.Registry, redactor redact.Redactor, jobService JobService, resumeService ResumeService) InterviewPrepService {
+	return &interviewPrepService{
+		llmProvider:   llmProvider,
+		store:         store,
+		prompts:       prompts,
+		redactor:      redactor,
+		jobService:    jobService,
+		resumeService: resumeService,
+	}
+}
+
+// GenerateInterviewPrep builds a prep pack from the job's stored description
+// and the selected resume and stores it on the job, replacing any earlier
+// pack.
+func (s *interviewPrepService) GenerateInterviewPrep(ctx context.Context, jobID string, request model.CompareRequest) (*model.InterviewPrep, error) {
+	ctx = usage.WithJob(ctx, jobID)
+	job, err := s.jobService.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Description == "" {
+		return nil, ErrNoJobPosting
+	}
+
+	resume, err := s.resumeService.ResolveResume(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// The title and company come from the posting, so they are kept to one
+	// short line before they go into the system prompt.
+	systemPrompt, err := prompt.InterviewPrep.Render(s.prompts, prompt.InterviewPrepVars{
+		Title:   utils.SanitizeField(job.Title, maxPromptField),
+		Company: utils.SanitizeField(job.Company, maxPromptField),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx = usage.WithOperation(ctx, systemPrompt.Name)
+
+	redacted, redaction := redactResume(ctx, s.redactor, resume.Resume)
+	bytes, err := json.Marshal(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling resume: %w", err)
+	}
+
+	messages := []map[string]string{
+		{
+			"role":    "system",
+			"content": systemPrompt.Text,
+		},
+		{
+			"role":    "user",
+			"content": "Resume:\n" + string(bytes),
+		},
+		{
+			"role":    "user",
+			"content": "Job Posting:\n" + utils.DelimitPosting(fmt.Sprintf("%s at %s\n%s", job.Title, job.Company, job.Description)),
+		},
+	}
+
+	var prep model.InterviewPrep
+	answeredBy, err := chatJSON(ctx, s.llmProvider, model.Gemma2_9B_Instruct, messages, &prep)
+	if err != nil {
+		return nil, fmt.Errorf("error generating interview prep: %w", err)
+	}
+	if err := redaction.RestoreJSON(&prep); err != nil {
+		return nil, fmt.Errorf("error generating interview prep: %w", err)
+	}
+
+	// Stories must point at a real experience entry; the resume, not the
+	// model, supplies the company and title shown next to them.
+	stories := []model.StarStory{}
+	for _, story := range prep.StarStories {
+		if story.ExperienceIndex < 0 || story.ExperienceIndex >= len(resume.Resume.Experience) {
+			continue
+		}
+		entry := resume.Resume.Experience[story.ExperienceIndex]
+		story.Company = entry.Company
+		story.Title = entry.Title
+		stories = append(stories, story)
+	}
+	prep.StarStories = stories
+
+	prep.JobID = jobID
+	prep.ResumeID = resume.ResumeID
+	prep.ResumeVersion = resume.Version
+	prep.Model = answeredBy
+	prep.PromptVersion = systemPrompt.Version
+	prep.CreatedAt = time.Now().Format(time.RFC3339)
+
+	if err := s.store.Put(userCollection(ctx, interviewPrepCollection), jobID, prep); err != nil {
+		return nil, fmt.Errorf("error saving interview prep: %w", err)
+	}
+
+	return &prep, nil
+}
+
+func (s *interviewPrepService) GetInterviewPrep(ctx context.Context, jobID string) (*model.InterviewPrep, error) {
+	prep, err := interviewPrep(ctx, s.store, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if prep == nil {
+		return nil, ErrInterviewPrepNotFound
+	}
+	return prep, nil
+}
+
+// HandleStatusChange generates a prep pack with the default resume when a
+// job moves to Interview. It is registered as a JobService status hook and
+// runs in the background, so failures are only logged.
+func (s *interviewPrepService) HandleStatusChange(ctx context.Context, jobID string, status string) {
+	if status != interviewStatus {
+		return
+	}
+
+	if _, err := s.GenerateInterviewPrep(ctx, jobID, model.CompareRequest{}); err != nil {
+		log.Printf("Failed to generate interview prep for %s: %v", jobID, err)
+	}
+}
+
+func interviewPrep(ctx context.Context, jobStore store.Store, jobID string) (*model.InterviewPrep, error) {
+	var prep model.InterviewPrep
+	err := jobStore.Get(userCollection(ctx, interviewPrepCollection), jobID, &prep)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading interview prep: %w", err)
+	}
+	return &prep, nil
+}
@@ -1,48 +1,104 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"job-parser-backend/internal/client"
+	"job-parser-backend/internal/config"
+	"job-parser-backend/internal/llmcache"
 	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/skills"
+	"job-parser-backend/internal/store"
+	"job-parser-backend/internal/tenant"
+	"job-parser-backend/internal/tokens"
+	"job-parser-backend/internal/usage"
 	"job-parser-backend/internal/utils"
 	"log"
-	"os"
+	"strings"
 	"time"
 )
 
 type JobService interface {
-	SaveJob(job model.Job) (*model.Job, error)
-	checkIfJobPostingExists(url string) error
-	UpdateJob(pageID string, job model.Job) error
-	GetRecentlySavedJobs(status string) ([]model.Job, error)
-	GetStats(dateRange string) (*model.StatsResult, error)
-	GetStreak() (*model.StreakStats, error)
-	formatJobDescriptionToJSON(jobDescription string) (*model.Job, error)
-	CompareJobPosting(resume any, jobPosting string) (*model.JobComparison, error)
-	saveJobPosting(job *model.Job) (*model.Job, error)
+	SaveJob(ctx context.Context, job model.Job) (*model.Job, error)
+	checkIfJobPostingExists(ctx context.Context, url string) error
+	UpdateJob(ctx context.Context, pageID string, job model.Job) error
+	OnStatusChange(hook StatusHook)
+	GetJob(ctx context.Context, pageID string) (*model.Job, error)
+	GetComparisons(ctx context.Context, jobID string) ([]model.JobComparison, error)
+	GetRecentlySavedJobs(ctx context.Context, status string) ([]model.Job, error)
+	ExportJobs(ctx context.Context, status string, fn func(job model.Job) error) error
+	GetJobEvents(ctx context.Context) ([]model.Job, error)
+	GetAppliedJobs(ctx context.Context, from time.Time, to time.Time) ([]model.Job, error)
+	GetStats(ctx context.Context, dateRange string) (*model.StatsResult, error)
+	GetStreak(ctx context.Context) (*model.StreakStats, error)
+	formatJobDescriptionToJSON(ctx context.Context, jobDescription string, url string) (*model.Job, error)
+	CompareJobPosting(ctx context.Context, request model.CompareRequest) (*model.JobComparison, error)
+	saveJobPosting(ctx context.Context, job *model.Job) (*model.Job, error)
 }
 
+// StatusHook is called in the background after UpdateJob changes a job's
+// status, with the context of the update so it acts for the same user.
+type StatusHook func(ctx context.Context, jobID string, status string)
+
 type jobService struct {
-	notionClient client.NotionClient
-	groqClient   client.GroqClient
+	notion        config.Notion
+	notionClient  client.NotionClient
+	llm           LLM
+	store         store.Store
+	resumeService ResumeService
+	skillMatcher  skills.Matcher
+	statusHooks   []StatusHook
 }
 
-func NewJobService(notionClient client.NotionClient, groqClient client.GroqClient) JobService {
+const (
+	statusHistoryCollection = "status_history"
+	comparisonCollection    = "comparisons"
+	extractionCollection    = "extractions"
+)
+
+var (
+	ErrNoJobPosting = errors.New("no job posting given and the job has no saved description")
+	ErrJobNotFound  = errors.New("job not found")
+	// ErrNothingToUpdate and ErrUnknownEventType reject job updates that
+	// would not change anything or name a date column that does not exist.
+	ErrNothingToUpdate  = errors.New("nothing to update")
+	ErrUnknownEventType = errors.New("unknown event type")
+)
+
+// eventProperties maps job event types to the Notion date columns that hold
+// them. Databases created before events existed simply lack these columns.
+var eventProperties = map[string]string{
+	model.EventInterview: "Interview Date",
+	model.EventDeadline:  "Deadline",
+	model.EventFollowUp:  "Follow Up Date",
+}
+
+func NewJobService(notion config.Notion, notionClient client.NotionClient, llm LLM, store store.Store, resumeService ResumeService, skillMatcher skills.Matcher) JobService {
 	return &jobService{
-		notionClient: notionClient,
-		groqClient:   groqClient,
+		notion:        notion,
+		notionClient:  notionClient,
+		llm:           llm,
+		store:         store,
+		resumeService: resumeService,
+		skillMatcher:  skillMatcher,
 	}
 }
 
-func (s *jobService) SaveJob(job model.Job) (*model.Job, error) {
-	err := s.checkIfJobPostingExists(job.URL)
+func (s *jobService) SaveJob(ctx context.Context, job model.Job) (*model.Job, error) {
+	err := s.checkIfJobPostingExists(ctx, job.URL)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := s.formatJobDescriptionToJSON(job.Description)
+	// The extraction runs before the job has a page ID to attribute it to.
+	var jobID string
+	ctx, attributeUsage := usage.WithPendingJob(ctx)
+	defer func() { attributeUsage(jobID) }()
+
+	res, err := s.formatJobDescriptionToJSON(ctx, job.Description, job.URL)
 	if err != nil {
 		return nil, err
 	}
@@ -55,108 +111,466 @@ func (s *jobService) SaveJob(job model.Job) (*model.Job, error) {
 		Title:       res.Title,
 	}
 
-	savedJob, err := s.saveJobPosting(parsedJob)
+	savedJob, err := s.saveJobPosting(ctx, parsedJob)
 	if err != nil {
 		return nil, err
 	}
+	jobID = savedJob.ID
+
+	if err := s.store.Put(extractionCollection, savedJob.ID, res.Extraction); err != nil {
+		log.Printf("Failed to record extraction for %s: %v", savedJob.ID, err)
+	}
+	savedJob.Extraction = res.Extraction
 
 	return savedJob, nil
 }
 
-func (s *jobService) formatJobDescriptionToJSON(jobDescription string) (*model.Job, error) {
-	body := map[string]any{
-		"messages": []map[string]string{
+func (s *jobService) formatJobDescriptionToJSON(ctx context.Context, jobDescription string, url string) (*model.Job, error) {
+	return ExtractJob(ctx, s.llm, model.Mixtral_Saba_24b, jobDescription, url)
+}
+
+// ExtractJob asks the model for a job's title, company, country and a
+// summary of its requirements. It is the LLM step of SaveJob, exported so
+// cmd/eval measures exactly what the server runs.
+//
+// Boilerplate is stripped first. Postings that still do not fit the model's
+// context are split into chunks that are extracted separately and merged.
+// modelName is used when llm has no fallback chain for extraction; chunks
+// are sized for the first model of the chain, so fallbacks should have at
+// least its context.
+//
+// The posting is untrusted, so it is sanitized and delimited in the prompt,
+// and the result is flagged when the posting seems to address the model. A
+// company that appears in neither the posting, outside the flagged passages,
+// nor sourceURL is discarded, since the model can only have been told or
+// guessed it.
+func ExtractJob(ctx context.Context, llm LLM, modelName string, jobDescription string, sourceURL string) (*model.Job, error) {
+	systemPrompt, err := prompt.ExtractJob.Render(llm.Prompts, prompt.NoVars{})
+	if err != nil {
+		return nil, err
+	}
+	ctx = usage.WithOperation(ctx, systemPrompt.Name)
+
+	posting := utils.StripBoilerplate(utils.SanitizePosting(jobDescription))
+	if posting == "" {
+		posting = utils.SanitizePosting(jobDescription)
+	}
+
+	primary := llm.steps(ctx, systemPrompt.Name, modelName)[0]
+	chunks, err := fitToBudget(llm.Tokens, primary.Model, systemPrompt.Text, posting)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) > maxExtractionChunks {
+		log.Printf("Posting needs %d chunks for %s, extracting the first %d", len(chunks), primary.Model, maxExtractionChunks)
+		chunks = chunks[:maxExtractionChunks]
+	}
+
+	parts := make([]model.Job, 0, len(chunks))
+	answeredBy := answer{Provider: primary.Provider.Name(), Model: primary.Model}
+	for _, chunk := range chunks {
+		messages := []map[string]string{
 			{
 				"role":    "system",
-				"content": utils.FormatDataToJsonPrompt,
+				"content": systemPrompt.Text,
 			},
 			{
 				"role":    "user",
-				"content": jobDescription,
+				"content": utils.DelimitPosting(chunk),
 			},
-		},
-		"model":  model.Mixtral_Saba_24b,
-		"stream": false,
-		"response_format": map[string]string{
-			"type": "json_object",
-		},
+		}
+
+		var part model.Job
+		chunkAnswer, err := fallbackChatJSON(ctx, llm, systemPrompt.Name, systemPrompt.Version, modelName, messages, &part, func() error {
+			return validateExtraction(part)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error extracting job: %w", err)
+		}
+		// A chunk answered by a fallback or downgraded model makes the whole
+		// extraction a fallback one.
+		if chunkAnswer != (answer{Provider: primary.Provider.Name(), Model: primary.Model}) {
+			answeredBy = chunkAnswer
+		}
+		parts = append(parts, part)
 	}
 
-	response, err := s.groqClient.POST("", body)
+	job := mergeExtractions(parts)
+	job.Extraction = &model.Extraction{
+		Provider:      answeredBy.Provider,
+		Model:         answeredBy.Model,
+		PromptVersion: systemPrompt.Version,
+		ExtractedAt:   time.Now().Format(time.RFC3339),
+		InputTokens:   llm.Tokens.Estimate(primary.Model, posting),
+		Chunks:        len(chunks),
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("error making Groq request: %w", err)
+	if matches := utils.DetectInjection(jobDescription); len(matches) > 0 {
+		log.Printf("Posting appears to contain instructions to the model: %q", matches)
+		job.Extraction.Flags = append(job.Extraction.Flags, model.FlagPromptInjection)
+	}
+	if !utils.CompanyInSource(job.Company, utils.WithoutInjection(jobDescription), sourceURL) {
+		log.Printf("Extracted company %q does not appear in the posting or its URL, discarding it", job.Company)
+		job.Extraction.Flags = append(job.Extraction.Flags, model.FlagCompanyNotInSource)
+		job.Company = ""
+	}
+
+	return &job, nil
+}
+
+// validateExtraction rejects the empty object small models sometimes return
+// in JSON mode, so the next model in the chain gets a chance.
+func validateExtraction(job model.Job) error {
+	if strings.TrimSpace(job.Title) == "" && strings.TrimSpace(job.Company) == "" && strings.TrimSpace(job.Description) == "" {
+		return errors.New("no title, company or description")
+	}
+	return nil
+}
+
+// maxExtractionChunks bounds the calls made for one posting. Anything past
+// it is almost always more boilerplate.
+const maxExtractionChunks = 6
+
+// minChunkTokens is the smallest chunk worth sending; a smaller budget
+// means the limits are misconfigured.
+const minChunkTokens = 256
+
+// fitToBudget returns posting as a single chunk when it fits next to the
+// system prompt, and split into chunks that each fit otherwise.
+func fitToBudget(estimator tokens.Estimator, modelName string, systemPrompt string, posting string) ([]string, error) {
+	budget := estimator.InputBudget(modelName, []map[string]string{{"content": systemPrompt}})
+	if budget < minChunkTokens {
+		return nil, fmt.Errorf("the %s context is too small for the prompt: %d tokens left for input", modelName, budget)
+	}
+
+	if estimator.Estimate(modelName, posting) <= budget {
+		return []string{posting}, nil
+	}
+	return estimator.Chunk(modelName, posting, budget), nil
+}
+
+// mergeExtractions combines the fields extracted from each chunk. The title
+// comes from the first chunk that has one, since postings lead with it;
+// company and country are the values most chunks agree on; descriptions are
+// concatenated without repeated lines.
+func mergeExtractions(parts []model.Job) model.Job {
+	if len(parts) == 1 {
+		return parts[0]
 	}
 
 	var job model.Job
-	if err := json.Unmarshal([]byte(response.Choices[0].Message.Content), &job); err != nil {
-		return nil, fmt.Errorf("error decoding job JSON: %w", err)
+	companies := make([]string, 0, len(parts))
+	countries := make([]string, 0, len(parts))
+	var description []string
+	seen := map[string]bool{}
+
+	for _, part := range parts {
+		if job.Title == "" {
+			job.Title = strings.TrimSpace(part.Title)
+		}
+		companies = append(companies, part.Company)
+		countries = append(countries, part.Country)
+
+		for _, line := range strings.Split(part.Description, "\n") {
+			key := strings.ToLower(strings.TrimSpace(line))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			description = append(description, strings.TrimSpace(line))
+		}
 	}
 
-	return &job, nil
+	job.Company = mostCommon(companies)
+	job.Country = mostCommon(countries)
+	job.Description = strings.Join(description, "\n")
+	return job
+}
+
+// mostCommon returns the most frequent non-empty value, compared without
+// case, preferring the earliest on ties.
+func mostCommon(values []string) string {
+	counts := map[string]int{}
+	best, bestCount := "", 0
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		key := strings.ToLower(value)
+		counts[key]++
+		if counts[key] > bestCount {
+			best, bestCount = value, counts[key]
+		}
+	}
+	return best
+}
+
+// CompareJobPosting compares a resume with a job posting. When the request
+// names a job, the result is stored on it and reused until the resume
+// version or the posting text changes.
+func (s *jobService) CompareJobPosting(ctx context.Context, request model.CompareRequest) (*model.JobComparison, error) {
+	resume, err := s.resumeService.ResolveResume(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	jobPosting := request.JobPosting
+	if jobPosting == "" && request.JobID != "" {
+		job, err := s.GetJob(ctx, request.JobID)
+		if err != nil {
+			return nil, err
+		}
+		jobPosting = job.Description
+	}
+	if jobPosting == "" {
+		return nil, ErrNoJobPosting
+	}
+
+	bytes, err := json.Marshal(resume.Resume)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling resume: %w", err)
+	}
+
+	resumeHash := utils.ContentHash(string(bytes))
+	postingHash := utils.ContentHash(jobPosting)
+
+	if request.JobID != "" && !request.Refresh {
+		cached, err := s.storedComparison(ctx, request.JobID, resume.ResumeID)
+		if err != nil {
+			return nil, err
+		}
+		if cached != nil && comparisonIsCurrent(*cached, *resume, resumeHash, postingHash, prompt.CompareJobPosting.Version(s.llm.Prompts)) {
+			cached.Cached = true
+			return cached, nil
+		}
+	}
+
+	if request.JobID != "" {
+		ctx = usage.WithJob(ctx, request.JobID)
+	}
+	if request.Refresh {
+		ctx = llmcache.WithBypass(ctx)
+	}
+
+	jobComparison, err := CompareResume(ctx, s.llm, model.Gemma2_9B_Instruct, resume.Resume, jobPosting)
+	if err != nil {
+		return nil, err
+	}
+
+	keywordMatch := s.skillMatcher.Match(resumeText(resume.Resume), jobPosting)
+	jobComparison.KeywordMatch = &keywordMatch
+	jobComparison.ScoreDifference = jobComparison.MatchScore - keywordMatch.Score
+
+	jobComparison.JobID = request.JobID
+	jobComparison.ResumeID = resume.ResumeID
+	jobComparison.ResumeVersion = resume.Version
+	jobComparison.ResumeHash = resumeHash
+	jobComparison.PostingHash = postingHash
+
+	if request.JobID != "" {
+		if err := s.saveComparison(ctx, *jobComparison); err != nil {
+			return nil, err
+		}
+	}
+
+	return jobComparison, nil
 }
 
-func (s *jobService) CompareJobPosting(resume any, jobPosting string) (*model.JobComparison, error) {
+// CompareResume asks the model how well a resume matches a posting. It is
+// the LLM step of CompareJobPosting, exported so cmd/eval measures exactly
+// what the server runs.
+//
+// A score needs the whole posting, so instead of chunking, postings that do
+// not fit next to the resume are cut off at the budget of the first model
+// of the fallback chain, or of modelName when there is no chain.
+func CompareResume(ctx context.Context, llm LLM, modelName string, resume model.Resume, jobPosting string) (*model.JobComparison, error) {
+	systemPrompt, err := prompt.CompareJobPosting.Render(llm.Prompts, prompt.NoVars{})
+	if err != nil {
+		return nil, err
+	}
+	ctx = usage.WithOperation(ctx, systemPrompt.Name)
+
+	resume, redaction := redactResume(ctx, llm.Redactor, resume)
 	bytes, err := json.Marshal(resume)
 	if err != nil {
 		return nil, fmt.Errorf("error marshalling resume: %w", err)
 	}
 
-	resumeString := string(bytes)
+	untrusted := jobPosting
+	jobPosting = utils.SanitizePosting(jobPosting)
+	if stripped := utils.StripBoilerplate(jobPosting); stripped != "" {
+		jobPosting = stripped
+	}
+	primary := llm.steps(ctx, systemPrompt.Name, modelName)[0]
+	chunks, err := fitToBudget(llm.Tokens, primary.Model, systemPrompt.Text+"\nResume:\n"+string(bytes), jobPosting)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) > 1 {
+		log.Printf("Posting does not fit %s next to the resume, comparing only the first of %d chunks", primary.Model, len(chunks))
+		jobPosting = chunks[0]
+	}
 
-	body := map[string]any{
-		"messages": []map[string]string{
-			{
-				"role":    "system",
-				"content": utils.CompareJobPostingPrompt,
-			},
-			{
-				"role":    "user",
-				"content": "Resume:\n" + resumeString,
-			},
-			{
-				"role":    "user",
-				"content": "Job Posting:\n" + jobPosting,
-			},
+	messages := []map[string]string{
+		{
+			"role":    "system",
+			"content": systemPrompt.Text,
+		},
+		{
+			"role":    "user",
+			"content": "Resume:\n" + string(bytes),
 		},
-		"model":  model.Gemma2_9B_Instruct,
-		"stream": false,
-		"response_format": map[string]string{
-			"type": "json_object",
+		{
+			"role":    "user",
+			"content": "Job Posting:\n" + utils.DelimitPosting(jobPosting),
 		},
 	}
 
-	response, err := s.groqClient.POST("", body)
-
+	var jobComparison model.JobComparison
+	answeredBy, err := fallbackChatJSON(ctx, llm, systemPrompt.Name, systemPrompt.Version, modelName, messages, &jobComparison, func() error {
+		return validateComparison(jobComparison)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error making Groq request: %w", err)
+		return nil, fmt.Errorf("error comparing job posting: %w", err)
+	}
+	if err := redaction.RestoreJSON(&jobComparison); err != nil {
+		return nil, fmt.Errorf("error comparing job posting: %w", err)
 	}
 
-	var jobComparison model.JobComparison
-	if err := json.Unmarshal([]byte(response.Choices[0].Message.Content), &jobComparison); err != nil {
-		return nil, fmt.Errorf("error decoding inner job comparison JSON: %w", err)
+	jobComparison.Provider = answeredBy.Provider
+	jobComparison.Model = answeredBy.Model
+	jobComparison.PromptVersion = systemPrompt.Version
+	jobComparison.ComparedAt = time.Now().Format(time.RFC3339)
+	if matches := utils.DetectInjection(untrusted); len(matches) > 0 {
+		log.Printf("Posting compared with %s appears to contain instructions to the model: %q", answeredBy.Model, matches)
+		jobComparison.Flags = append(jobComparison.Flags, model.FlagPromptInjection)
 	}
 
 	return &jobComparison, nil
 }
 
-func (s *jobService) GetRecentlySavedJobs(status string) ([]model.Job, error) {
-	notionDatabaseId := os.Getenv("NOTION_DATABASE_ID")
+// validateComparison rejects scores outside 0-100 and replies without the
+// recommendations the prompt asks for, which are usually a truncated or
+// empty object.
+func validateComparison(comparison model.JobComparison) error {
+	if comparison.MatchScore < 0 || comparison.MatchScore > 100 {
+		return fmt.Errorf("match score %d outside 0-100", comparison.MatchScore)
+	}
+	if comparison.Recommendations == nil {
+		return errors.New("no recommendations")
+	}
+	return nil
+}
 
-	var body map[string]any
-	if status == "" {
-		body = map[string]any{}
-	} else {
-		body = map[string]any{
-			"filter": map[string]any{
-				"property": "Status",
-				"status": map[string]string{
-					"equals": status,
-				},
-			},
+// GetJob returns a single job with its status history, how it was extracted,
+// and the comparisons, cover letters and interview prep stored on it.
+func (s *jobService) GetJob(ctx context.Context, pageID string) (*model.Job, error) {
+	if !validPageID(pageID) {
+		return nil, ErrJobNotFound
+	}
+	page, err := s.notionClient.GetNotionPage(ctx, pageID, nil)
+	if errors.Is(err, client.ErrNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job := jobFromNotion(page.ID, page.CreatedTime, page.Properties)
+
+	job.StatusHistory, err = s.statusHistory(pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Comparisons, err = s.comparisons(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	job.CoverLetters, err = coverLetters(ctx, s.store, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	job.InterviewPrep, err = interviewPrep(ctx, s.store, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	var extraction model.Extraction
+	err = s.store.Get(extractionCollection, pageID, &extraction)
+	if err == nil {
+		job.Extraction = &extraction
+	} else if !errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("error reading extraction: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetComparisons returns the comparisons the user of ctx stored on a job,
+// one per resume.
+func (s *jobService) GetComparisons(ctx context.Context, jobID string) ([]model.JobComparison, error) {
+	return s.comparisons(ctx, jobID)
+}
+
+// comparisonIsCurrent reports whether a stored comparison was made with the
+// same resume version, posting text and prompt version as those given.
+func comparisonIsCurrent(comparison model.JobComparison, resume model.ResumeVersion, resumeHash string, postingHash string, promptVersion string) bool {
+	return comparison.ResumeVersion == resume.Version && comparison.ResumeHash == resumeHash && comparison.PostingHash == postingHash &&
+		comparison.PromptVersion == promptVersion
+}
+
+func (s *jobService) comparisons(ctx context.Context, jobID string) ([]model.JobComparison, error) {
+	var comparisons []model.JobComparison
+	err := s.store.Get(userCollection(ctx, comparisonCollection), jobID, &comparisons)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("error reading comparisons: %w", err)
+	}
+	return comparisons, nil
+}
+
+// storedComparison returns the comparison stored on a job for a resume, or
+// nil if there is none. Inline resumes share the empty resume ID.
+func (s *jobService) storedComparison(ctx context.Context, jobID string, resumeID string) (*model.JobComparison, error) {
+	comparisons, err := s.comparisons(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	for _, comparison := range comparisons {
+		if comparison.ResumeID == resumeID {
+			return &comparison, nil
 		}
 	}
-	response, err := s.notionClient.GetNotionDatabase(notionDatabaseId, body)
+	return nil, nil
+}
+
+// saveComparison stores comparison on its job, replacing any earlier
+// comparison against the same resume.
+func (s *jobService) saveComparison(ctx context.Context, comparison model.JobComparison) error {
+	return s.store.Update(userCollection(ctx, comparisonCollection), comparison.JobID, func(current json.RawMessage) (any, error) {
+		var comparisons []model.JobComparison
+		if current != nil {
+			if err := json.Unmarshal(current, &comparisons); err != nil {
+				return nil, fmt.Errorf("error decoding comparisons: %w", err)
+			}
+		}
+
+		for i, existing := range comparisons {
+			if existing.ResumeID == comparison.ResumeID {
+				comparisons[i] = comparison
+				return comparisons, nil
+			}
+		}
+		return append(comparisons, comparison), nil
+	})
+}
+
+func (s *jobService) GetRecentlySavedJobs(ctx context.Context, status string) ([]model.Job, error) {
+	body := statusFilter(status)
+	response, err := s.notionClient.GetNotionDatabase(ctx, s.databaseID(ctx), body)
 
 	if err != nil {
 		return nil, err
@@ -165,24 +579,230 @@ func (s *jobService) GetRecentlySavedJobs(status string) ([]model.Job, error) {
 	var recentJobs []model.Job
 
 	for _, content := range response.Results {
-		recentJobs = append(recentJobs, model.Job{
-			ID:          content.ID,
-			Country:     content.Properties.Country.Select.Name,
-			Company:     content.Properties.Company.Select.Name,
-			URL:         content.Properties.URL.URL,
-			Status:      content.Properties.Status.Status.Name,
-			Title:       content.Properties.Link.Title[0].PlainText,
-			Description: content.Properties.Description.RichText[0].PlainText,
-		})
+		recentJobs = append(recentJobs, jobFromNotion(content.ID, content.CreatedTime, content.Properties))
 	}
 
 	return recentJobs, nil
 
 }
 
-func (s *jobService) checkIfJobPostingExists(jobPostingUrl string) error {
-	notionDatabaseId := os.Getenv("NOTION_DATABASE_ID")
+// ExportJobs walks every job matching status, one Notion result page at a
+// time, and passes each job with its status history to fn. Stopping early is
+// done by returning an error from fn.
+func (s *jobService) ExportJobs(ctx context.Context, status string, fn func(job model.Job) error) error {
+	return s.forEachJob(ctx, statusFilter(status), func(job model.Job) error {
+		history, err := s.statusHistory(job.ID)
+		if err != nil {
+			return err
+		}
+		job.StatusHistory = history
+
+		return fn(job)
+	})
+}
+
+// GetJobEvents returns every job that has at least one dated event.
+func (s *jobService) GetJobEvents(ctx context.Context) ([]model.Job, error) {
+	var jobs []model.Job
+
+	err := s.forEachJob(ctx, map[string]any{}, func(job model.Job) error {
+		if len(job.Events) > 0 {
+			jobs = append(jobs, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetAppliedJobs returns every job whose Applied Date is between from and
+// to, inclusive, whenever the job was saved.
+func (s *jobService) GetAppliedJobs(ctx context.Context, from time.Time, to time.Time) ([]model.Job, error) {
+	body := map[string]any{
+		"filter": map[string]any{
+			"and": []map[string]any{
+				{
+					"property": "Applied Date",
+					"date":     map[string]any{"on_or_after": from.Format(time.RFC3339)},
+				},
+				{
+					"property": "Applied Date",
+					"date":     map[string]any{"on_or_before": to.Format(time.RFC3339)},
+				},
+			},
+		},
+	}
+
+	var jobs []model.Job
+	err := s.forEachJob(ctx, body, func(job model.Job) error {
+		// Notion compares dates by day, so the bounds are checked again here.
+		applied, err := time.Parse(time.RFC3339, job.AppliedDate)
+		if err != nil || applied.Before(from) || applied.After(to) {
+			return nil
+		}
+		jobs = append(jobs, job)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// forEachJob pages through the Notion query described by body, oldest job
+// first, so callers never hold more than one result page in memory.
+func (s *jobService) forEachJob(ctx context.Context, body map[string]any, fn func(job model.Job) error) error {
+	notionDatabaseId := s.databaseID(ctx)
+
+	body["page_size"] = 100
+	body["sorts"] = []map[string]any{
+		{
+			"timestamp": "created_time",
+			"direction": "ascending",
+		},
+	}
+
+	for {
+		response, err := s.notionClient.GetNotionDatabase(ctx, notionDatabaseId, body)
+		if err != nil {
+			return err
+		}
+
+		for _, content := range response.Results {
+			if err := fn(jobFromNotion(content.ID, content.CreatedTime, content.Properties)); err != nil {
+				return err
+			}
+		}
+
+		if !response.HasMore || response.NextCursor == "" {
+			return nil
+		}
+		body["start_cursor"] = response.NextCursor
+	}
+}
+
+// validPageID reports whether id has the form of a Notion page ID, 32 hex
+// digits with or without the dashes of a UUID. Notion rejects other IDs
+// as malformed rather than unknown.
+func validPageID(id string) bool {
+	id = strings.ReplaceAll(id, "-", "")
+	if len(id) != 32 {
+		return false
+	}
+	for _, r := range id {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *jobService) statusHistory(pageID string) ([]model.StatusChange, error) {
+	var history []model.StatusChange
+	err := s.store.Get(statusHistoryCollection, pageID, &history)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("error reading status history: %w", err)
+	}
+	return history, nil
+}
+
+func (s *jobService) recordStatusChange(pageID string, change model.StatusChange) error {
+	return s.store.Update(statusHistoryCollection, pageID, func(current json.RawMessage) (any, error) {
+		var history []model.StatusChange
+		if current != nil {
+			if err := json.Unmarshal(current, &history); err != nil {
+				return nil, fmt.Errorf("error decoding status history: %w", err)
+			}
+		}
+		return append(history, change), nil
+	})
+}
 
+// databaseID returns the Notion database of the request's credentials, or
+// the server's own.
+func (s *jobService) databaseID(ctx context.Context) string {
+	if credentials, ok := tenant.CredentialsFrom(ctx); ok {
+		return credentials.NotionDatabaseID
+	}
+	return s.notion.DatabaseID
+}
+
+// userCollection returns the collection holding the user of ctx's share of
+// collection, for data derived from their resumes. The server's own user
+// keeps the unprefixed collection, so single-user stores need no migration.
+func userCollection(ctx context.Context, collection string) string {
+	if key := tenant.Key(ctx); key != "" {
+		return collection + "/" + key
+	}
+	return collection
+}
+
+// statusFilter builds the Notion query body shared by the list and export
+// endpoints. An empty status matches every job.
+func statusFilter(status string) map[string]any {
+	if status == "" {
+		return map[string]any{}
+	}
+	return map[string]any{
+		"filter": map[string]any{
+			"property": "Status",
+			"status": map[string]string{
+				"equals": status,
+			},
+		},
+	}
+}
+
+// jobFromNotion maps Notion page properties to a job, tolerating rows where
+// the title or description were left empty.
+func jobFromNotion(id string, createdTime string, properties model.NotionProperties) model.Job {
+	job := model.Job{
+		ID:          id,
+		Country:     properties.Country.Select.Name,
+		Company:     properties.Company.Select.Name,
+		URL:         properties.URL.URL,
+		Status:      properties.Status.Status.Name,
+		CreatedDate: createdTime,
+	}
+
+	if len(properties.Link.Title) > 0 {
+		job.Title = properties.Link.Title[0].PlainText
+	}
+	if len(properties.Description.RichText) > 0 {
+		job.Description = properties.Description.RichText[0].PlainText
+	}
+	if properties.AppliedDate.Date != nil {
+		job.AppliedDate = properties.AppliedDate.Date.Start
+	}
+
+	events := []struct {
+		eventType string
+		date      model.NotionDate
+	}{
+		{model.EventInterview, properties.InterviewDate},
+		{model.EventDeadline, properties.Deadline},
+		{model.EventFollowUp, properties.FollowUpDate},
+	}
+	for _, event := range events {
+		if event.date.Date == nil || event.date.Date.Start == "" {
+			continue
+		}
+		job.Events = append(job.Events, model.JobEvent{
+			Type:     event.eventType,
+			Start:    event.date.Date.Start,
+			End:      event.date.Date.End,
+			TimeZone: event.date.Date.TimeZone,
+		})
+	}
+
+	return job
+}
+
+func (s *jobService) checkIfJobPostingExists(ctx context.Context, jobPostingUrl string) error {
 	body := map[string]any{
 		"filter": map[string]any{
 			"property": "URL",
@@ -192,7 +812,7 @@ func (s *jobService) checkIfJobPostingExists(jobPostingUrl string) error {
 		},
 	}
 
-	response, err := s.notionClient.GetNotionDatabase(notionDatabaseId, body)
+	response, err := s.notionClient.GetNotionDatabase(ctx, s.databaseID(ctx), body)
 
 	if err != nil {
 		return err
@@ -205,9 +825,7 @@ func (s *jobService) checkIfJobPostingExists(jobPostingUrl string) error {
 	return nil
 }
 
-func (s *jobService) saveJobPosting(data *model.Job) (*model.Job, error) {
-	notionDatabaseId := os.Getenv("NOTION_DATABASE_ID")
-
+func (s *jobService) saveJobPosting(ctx context.Context, data *model.Job) (*model.Job, error) {
 	body := map[string]any{
 		"properties": map[string]any{
 			"Link": map[string]any{
@@ -252,57 +870,97 @@ func (s *jobService) saveJobPosting(data *model.Job) (*model.Job, error) {
 		},
 	}
 
-	page, err := s.notionClient.CreateNotionPage(notionDatabaseId, body)
+	page, err := s.notionClient.CreateNotionPage(ctx, s.databaseID(ctx), body)
 
 	if err != nil {
 		return nil, err
 	}
 
-	savedJob := &model.Job{
-		Country:     page.Properties.Country.Select.Name,
-		Company:     page.Properties.Company.Select.Name,
-		URL:         page.Properties.URL.URL,
-		Title:       page.Properties.Link.Title[0].PlainText,
-		Status:      page.Properties.Status.Status.Name,
-		Description: page.Properties.Description.RichText[0].PlainText,
-	}
+	savedJob := jobFromNotion(page.ID, page.CreatedTime, page.Properties)
 
-	return savedJob, nil
+	return &savedJob, nil
 
 }
 
-func (s *jobService) UpdateJob(pageId string, job model.Job) error {
+func (s *jobService) UpdateJob(ctx context.Context, pageId string, job model.Job) error {
 	today := time.Now()
+	properties := map[string]any{}
 	body := map[string]any{
-		"properties": map[string]any{
-			"Status": map[string]any{
-				"status": map[string]any{
-					"name": job.Status,
-				},
+		"properties": properties,
+	}
+
+	if job.Status != "" {
+		properties["Status"] = map[string]any{
+			"status": map[string]any{
+				"name": job.Status,
 			},
-		},
+		}
 	}
 
 	if job.Status == "Applied" {
-		body["properties"].(map[string]any)["Applied Date"] = map[string]any{
+		properties["Applied Date"] = map[string]any{
 			"date": map[string]any{
 				"start": today.Format(time.RFC3339), // Equivalent to toISOString()
 			},
 		}
 	}
 
-	_, err := s.notionClient.UpdateNotionPage(pageId, body)
+	// Only the event types sent are touched; an empty start clears the date.
+	for _, event := range job.Events {
+		property, ok := eventProperties[event.Type]
+		if !ok {
+			return fmt.Errorf("%w %q", ErrUnknownEventType, event.Type)
+		}
+
+		if event.Start == "" {
+			properties[property] = map[string]any{"date": nil}
+			continue
+		}
+
+		date := map[string]any{"start": event.Start}
+		if event.End != "" {
+			date["end"] = event.End
+		}
+		if event.TimeZone != "" {
+			date["time_zone"] = event.TimeZone
+		}
+		properties[property] = map[string]any{"date": date}
+	}
+
+	if len(properties) == 0 {
+		return ErrNothingToUpdate
+	}
+
+	_, err := s.notionClient.UpdateNotionPage(ctx, pageId, body)
 
 	if err != nil {
 		return err
 	}
 
+	if job.Status != "" {
+		change := model.StatusChange{Status: job.Status, ChangedAt: today.Format(time.RFC3339)}
+		if err := s.recordStatusChange(pageId, change); err != nil {
+			log.Printf("Failed to record status change for %s: %v", pageId, err)
+		}
+
+		// Hooks outlive the request, so they keep its values but not its
+		// cancellation.
+		hookCtx := context.WithoutCancel(ctx)
+		for _, hook := range s.statusHooks {
+			go hook(hookCtx, pageId, job.Status)
+		}
+	}
+
 	return nil
 }
 
-func (s *jobService) GetStats(dateRange string) (*model.StatsResult, error) {
-	databaseID := os.Getenv("NOTION_DATABASE_ID")
+// OnStatusChange registers hook to run after every status change. Hooks must
+// be registered before the server starts handling requests.
+func (s *jobService) OnStatusChange(hook StatusHook) {
+	s.statusHooks = append(s.statusHooks, hook)
+}
 
+func (s *jobService) GetStats(ctx context.Context, dateRange string) (*model.StatsResult, error) {
 	var dateFilter map[string]any
 	rangeNumber := 30 // Default to 30 days
 	switch dateRange {
@@ -325,7 +983,7 @@ func (s *jobService) GetStats(dateRange string) (*model.StatsResult, error) {
 		},
 	}
 
-	response, err := s.notionClient.GetNotionDatabase(databaseID, body)
+	response, err := s.notionClient.GetNotionDatabase(ctx, s.databaseID(ctx), body)
 
 	if err != nil {
 		return nil, err
@@ -378,9 +1036,7 @@ func (s *jobService) GetStats(dateRange string) (*model.StatsResult, error) {
 	return stats, nil
 }
 
-func (s *jobService) GetStreak() (*model.StreakStats, error) {
-	databaseID := os.Getenv("NOTION_DATABASE_ID")
-
+func (s *jobService) GetStreak(ctx context.Context) (*model.StreakStats, error) {
 	body := map[string]any{
 		"sorts": []map[string]any{
 			{
@@ -396,7 +1052,7 @@ func (s *jobService) GetStreak() (*model.StreakStats, error) {
 		},
 	}
 
-	response, err := s.notionClient.GetNotionDatabase(databaseID, body)
+	response, err := s.notionClient.GetNotionDatabase(ctx, s.databaseID(ctx), body)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/client"
+	"job-parser-backend/internal/fallback"
+	"job-parser-backend/internal/llmcache"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/redact"
+	"job-parser-backend/internal/tokens"
+	"job-parser-backend/internal/usage"
+	"log"
+	"reflect"
+)
+
+// LLM bundles what the extraction and comparison calls need. Cache may be
+// nil to always call the provider. Fallbacks may be nil, or lack a chain for
+// a task, in which case the task calls Provider with the caller's model.
+// Redactor may be nil to send resumes unredacted.
+type LLM struct {
+	Provider  client.LLMProvider
+	Cache     llmcache.Cache
+	Prompts   prompt.Registry
+	Tokens    tokens.Estimator
+	Fallbacks *fallback.Chains
+	Redactor  redact.Redactor
+}
+
+// redactResume replaces the personal data in resume with placeholders before
+// it is sent to a model. A nil redactor leaves it as is.
+func redactResume(ctx context.Context, redactor redact.Redactor, resume model.Resume) (model.Resume, *redact.Redaction) {
+	if redactor == nil {
+		return resume, nil
+	}
+	return redactor.Resume(ctx, resume)
+}
+
+// redactText is redactResume for free text.
+func redactText(ctx context.Context, redactor redact.Redactor, text string) (string, *redact.Redaction) {
+	if redactor == nil {
+		return text, nil
+	}
+	return redactor.Text(ctx, text)
+}
+
+// steps returns the models tried in order for task. Models of providers
+// that cannot serve the request of ctx are left out, unless that leaves
+// none, so the chain fails with their reason.
+func (llm LLM) steps(ctx context.Context, task string, modelName string) []fallback.Step {
+	steps := llm.Fallbacks.For(task)
+	if len(steps) == 0 {
+		return []fallback.Step{{Provider: llm.Provider, Model: modelName}}
+	}
+
+	var usable []fallback.Step
+	for _, step := range steps {
+		if client.ServesRequest(ctx, step.Provider) {
+			usable = append(usable, step)
+		}
+	}
+	if len(usable) == 0 {
+		return steps
+	}
+	return usable
+}
+
+// answer records which provider and model produced a reply.
+type answer struct {
+	Provider string
+	Model    string
+}
+
+// fallbackChatJSON runs cachedChatJSON down the fallback chain of task,
+// moving to the next model when a call fails, times out or returns a reply
+// that validate rejects. out is reset between attempts. A spent budget or
+// a cancelled request stops the chain, since every later model would fail
+// the same way.
+func fallbackChatJSON(ctx context.Context, llm LLM, task string, promptVersion string, modelName string, messages []map[string]string, out any, validate func() error) (answer, error) {
+	steps := llm.steps(ctx, task, modelName)
+
+	var errs []error
+	for i, step := range steps {
+		if i > 0 {
+			reflect.ValueOf(out).Elem().SetZero()
+		}
+
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout := llm.Fallbacks.Timeout(); timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		answeredBy, err := cachedChatJSON(attemptCtx, llm.Cache, step.Provider, promptVersion, step.Model, messages, out, validate)
+		cancel()
+		if err == nil {
+			if i > 0 {
+				log.Printf("%s answered %s after %d failed models", step, task, i)
+			}
+			return answer{Provider: step.Provider.Name(), Model: answeredBy}, nil
+		}
+
+		if len(steps) == 1 || errors.Is(err, usage.ErrBudgetExceeded) || ctx.Err() != nil {
+			return answer{}, err
+		}
+		log.Printf("%s failed for %s: %v", step, task, err)
+		errs = append(errs, fmt.Errorf("%s: %w", step, err))
+	}
+
+	return answer{}, fmt.Errorf("all %d models failed: %w", len(steps), errors.Join(errs...))
+}
+
+// chatJSON sends messages to the LLM provider in JSON mode and
+// decodes the model's reply into out. It is shared by every LLM-backed
+// operation so request shape and error handling stay in one place. It
+// returns the model that answered, which differs from modelName when the
+// usage budget downgraded the call.
+func chatJSON(ctx context.Context, llmProvider client.LLMProvider, modelName string, messages []map[string]string, out any) (string, error) {
+	_, answeredBy, err := chat(ctx, llmProvider, modelName, messages, out, nil)
+	return answeredBy, err
+}
+
+// cachedChatJSON is chatJSON for deterministic operations. Replies are
+// served from llmCache when the prompt version, model and input match an
+// earlier call. A nil cache always calls the provider. Replies from a
+// downgraded model are not cached, so the requested model answers again
+// once the budget allows. validate, when not nil, checks the decoded reply;
+// rejected replies are neither cached nor served from the cache.
+func cachedChatJSON(ctx context.Context, llmCache llmcache.Cache, llmProvider client.LLMProvider, promptVersion string, modelName string, messages []map[string]string, out any, validate func() error) (string, error) {
+	if llmCache == nil {
+		_, answeredBy, err := chat(ctx, llmProvider, modelName, messages, out, validate)
+		return answeredBy, err
+	}
+
+	key := llmcache.NewKey(promptVersion, modelName, messages)
+	if content, ok := llmCache.Get(ctx, key); ok {
+		if err := json.Unmarshal([]byte(content), out); err == nil && (validate == nil || validate() == nil) {
+			return key.Model, nil
+		}
+		reflect.ValueOf(out).Elem().SetZero()
+	}
+
+	content, answeredBy, err := chat(ctx, llmProvider, modelName, messages, out, validate)
+	if err != nil {
+		return "", err
+	}
+
+	if answeredBy == modelName {
+		llmCache.Put(key, content)
+	}
+	return answeredBy, nil
+}
+
+// chat makes the request and decodes the reply into out, returning the raw
+// reply so it can be cached and the model that produced it.
+func chat(ctx context.Context, llmProvider client.LLMProvider, modelName string, messages []map[string]string, out any, validate func() error) (string, string, error) {
+	body := map[string]any{
+		"messages": messages,
+		"model":    modelName,
+		"stream":   false,
+		"response_format": map[string]string{
+			"type": "json_object",
+		},
+	}
+
+	response, err := llmProvider.ChatCompletion(ctx, body)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	content := response.Choices[0].Message.Content
+	if err := json.Unmarshal([]byte(content), out); err != nil {
+		return "", "", fmt.Errorf("error decoding model JSON: %w", err)
+	}
+	if validate != nil {
+		if err := validate(); err != nil {
+			return "", "", fmt.Errorf("invalid model reply: %w", err)
+		}
+	}
+
+	answeredBy := response.Model
+	if answeredBy == "" {
+		answeredBy = modelName
+	}
+	return content, answeredBy, nil
+}
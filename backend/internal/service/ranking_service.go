@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/skills"
+	"job-parser-backend/internal/tenant"
+	"job-parser-backend/internal/utils"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RankingService interface {
+	RankJobs(ctx context.Context, resumeID string) ([]model.RankedJob, error)
+}
+
+const (
+	// rankedStatus is the status of jobs that are still worth applying to.
+	rankedStatus = "Not Applied"
+
+	fitWeight       = 0.7
+	freshnessWeight = 0.15
+	urgencyWeight   = 0.15
+
+	// Postings older than freshnessDays no longer get a freshness bonus, and
+	// deadlines further out than urgencyDays are not yet urgent.
+	freshnessDays = 60
+	urgencyDays   = 14
+
+	comparisonQueueSize = 100
+)
+
+type comparisonTask struct {
+	jobID         string
+	resumeID      string
+	resumeVersion int
+	// tenant keeps the same comparison for different users apart.
+	tenant string
+}
+
+// queuedComparison carries a task with the context of the request that
+// queued it, so it runs with that user's credentials.
+type queuedComparison struct {
+	ctx  context.Context
+	task comparisonTask
+}
+
+type rankingService struct {
+	jobService    JobService
+	resumeService ResumeService
+	skillMatcher  skills.Matcher
+	prompts       prompt.Registry
+
+	queue    chan queuedComparison
+	mu       sync.Mutex
+	inFlight map[comparisonTask]bool
+}
+
+// NewRankingService starts a background worker that fills in model
+// comparisons missing when jobs are ranked.
+func NewRankingService(jobService JobService, resumeService ResumeService, skillMatcher skills.Matcher, prompts prompt.Registry) RankingService {
+	s := &rankingService{
+		jobService:    jobService,
+		resumeService: resumeService,
+		skillMatcher:  skillMatcher,
+		prompts:       prompts,
+		queue:         make(chan queuedComparison, comparisonQueueSize),
+		inFlight:      make(map[comparisonTask]bool),
+	}
+
+	go s.compareInBackground()
+
+	return s
+}
+
+// RankJobs scores every unapplied job against a stored resume, or the
+// default resume when resumeID is empty, and returns them highest priority
+// first. Stored comparisons are reused; jobs without a current one are
+// ranked on keyword score and queued for a model comparison, so repeated
+// calls improve as the background work completes.
+func (s *rankingService) RankJobs(ctx context.Context, resumeID string) ([]model.RankedJob, error) {
+	resume, err := s.resumeService.ResolveResume(ctx, model.CompareRequest{ResumeID: resumeID})
+	if err != nil {
+		return nil, err
+	}
+
+	bytes, err := json.Marshal(resume.Resume)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling resume: %w", err)
+	}
+	resumeHash := utils.ContentHash(string(bytes))
+	text := resumeText(resume.Resume)
+	promptVersion := prompt.CompareJobPosting.Version(s.prompts)
+	now := time.Now()
+
+	var ranked []model.RankedJob
+	err = s.jobService.ExportJobs(ctx, rankedStatus, func(job model.Job) error {
+		comparisons, err := s.jobService.GetComparisons(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+
+		var current *model.JobComparison
+		postingHash := utils.ContentHash(job.Description)
+		for i, comparison := range comparisons {
+			if comparison.ResumeID == resume.ResumeID && comparisonIsCurrent(comparison, *resume, resumeHash, postingHash, promptVersion) {
+				current = &comparisons[i]
+				break
+			}
+		}
+
+		if current == nil && job.Description != "" {
+			s.enqueue(ctx, comparisonTask{jobID: job.ID, resumeID: resume.ResumeID, resumeVersion: resume.Version})
+		}
+
+		job.StatusHistory = nil
+		ranked = append(ranked, s.rank(job, current, text, now))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Priority > ranked[j].Priority })
+
+	return ranked, nil
+}
+
+func (s *rankingService) rank(job model.Job, comparison *model.JobComparison, resumeText string, now time.Time) model.RankedJob {
+	ranked := model.RankedJob{Job: job}
+
+	keywordMatch := s.skillMatcher.Match(resumeText, job.Description)
+	ranked.KeywordScore = keywordMatch.Score
+
+	if comparison != nil {
+		llmScore := comparison.MatchScore
+		ranked.LLMScore = &llmScore
+		ranked.FitScore = (llmScore + keywordMatch.Score) / 2
+		ranked.Explanation = append(ranked.Explanation,
+			fmt.Sprintf("Fit %d: model score %d and keyword score %d averaged", ranked.FitScore, llmScore, keywordMatch.Score))
+	} else {
+		ranked.FitScore = keywordMatch.Score
+		ranked.Pending = job.Description != ""
+		ranked.Explanation = append(ranked.Explanation,
+			fmt.Sprintf("Fit %d: keyword score only, model comparison pending", ranked.FitScore))
+	}
+
+	if len(keywordMatch.Missing) > 0 {
+		ranked.Explanation = append(ranked.Explanation,
+			fmt.Sprintf("Missing skills: %s", strings.Join(keywordMatch.Missing, ", ")))
+	}
+
+	freshness := 0.0
+	if created, err := time.Parse(time.RFC3339, job.CreatedDate); err == nil {
+		ranked.AgeDays = int(now.Sub(created).Hours() / 24)
+		freshness = 100 * math.Max(0, 1-float64(ranked.AgeDays)/freshnessDays)
+		ranked.Explanation = append(ranked.Explanation,
+			fmt.Sprintf("Saved %d days ago: freshness %.0f", ranked.AgeDays, freshness))
+	}
+
+	urgency := 0.0
+	expired := false
+	if deadline, ok := deadlineOf(job); ok {
+		days := int(math.Floor(deadline.Sub(now).Hours() / 24))
+		ranked.DeadlineInDays = &days
+		switch {
+		case days < 0:
+			expired = true
+			ranked.Explanation = append(ranked.Explanation, fmt.Sprintf("Deadline passed %d days ago", -days))
+		case days <= urgencyDays:
+			urgency = 100 * (1 - float64(days)/urgencyDays)
+			ranked.Explanation = append(ranked.Explanation, fmt.Sprintf("Deadline in %d days: urgency %.0f", days, urgency))
+		default:
+			ranked.Explanation = append(ranked.Explanation, fmt.Sprintf("Deadline in %d days", days))
+		}
+	}
+
+	priority := fitWeight*float64(ranked.FitScore) + freshnessWeight*freshness + urgencyWeight*urgency
+	if expired {
+		// Keep expired postings visible but below everything still open.
+		priority /= 4
+	}
+	ranked.Priority = int(math.Round(priority))
+
+	return ranked
+}
+
+// deadlineOf returns the end of the job's deadline day, or the deadline time
+// itself when one was given.
+func deadlineOf(job model.Job) (time.Time, bool) {
+	for _, event := range job.Events {
+		if event.Type != model.EventDeadline {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, event.Start); err == nil {
+			return t, true
+		}
+		if t, err := time.ParseInLocation("2006-01-02", event.Start, time.Local); err == nil {
+			return t.Add(24*time.Hour - time.Second), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// enqueue schedules a comparison unless the same one is already waiting. A
+// full queue drops the task; it is queued again on the next ranking request.
+func (s *rankingService) enqueue(ctx context.Context, task comparisonTask) {
+	task.tenant = tenant.Key(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inFlight[task] {
+		return
+	}
+
+	select {
+	case s.queue <- queuedComparison{ctx: context.WithoutCancel(ctx), task: task}:
+		s.inFlight[task] = true
+	default:
+	}
+}
+
+func (s *rankingService) compareInBackground() {
+	for queued := range s.queue {
+		task := queued.task
+		_, err := s.jobService.CompareJobPosting(queued.ctx, model.CompareRequest{
+			JobID:         task.jobID,
+			ResumeID:      task.resumeID,
+			ResumeVersion: task.resumeVersion,
+		})
+		if err != nil {
+			log.Printf("Background comparison for job %s failed: %v", task.jobID, err)
+		}
+
+		s.mu.Lock()
+		delete(s.inFlight, task)
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/notify"
+	"job-parser-backend/internal/store"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ReminderService interface {
+	FindStaleJobs(ctx context.Context) ([]model.Reminder, error)
+	FindDueReminders(ctx context.Context) ([]model.Reminder, error)
+	SendDueReminders() error
+	// SetConfig replaces the config, from the next check on.
+	SetConfig(config ReminderConfig)
+}
+
+// ReminderConfig controls which jobs are considered stale.
+type ReminderConfig struct {
+	Threshold time.Duration
+	Statuses  []string
+}
+
+type reminderService struct {
+	jobService JobService
+	store      store.Store
+	notifiers  []notify.Notifier
+
+	// mu guards config, which can change while the server runs.
+	mu     sync.Mutex
+	config ReminderConfig
+}
+
+const reminderCollection = "reminders"
+
+func NewReminderService(jobService JobService, store store.Store, notifiers []notify.Notifier, config ReminderConfig) ReminderService {
+	return &reminderService{
+		jobService: jobService,
+		store:      store,
+		notifiers:  notifiers,
+		config:     config,
+	}
+}
+
+func (s *reminderService) SetConfig(config ReminderConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = config
+}
+
+// FindStaleJobs returns a reminder for every job that has been in one of the
+// configured statuses for longer than the threshold, whether or not it has
+// already been sent.
+func (s *reminderService) FindStaleJobs(ctx context.Context) ([]model.Reminder, error) {
+	now := time.Now()
+	var reminders []model.Reminder
+
+	s.mu.Lock()
+	config := s.config
+	s.mu.Unlock()
+
+	for _, status := range config.Statuses {
+		err := s.jobService.ExportJobs(ctx, status, func(job model.Job) error {
+			since, ok := statusSince(job)
+			if !ok || now.Sub(since) < config.Threshold {
+				return nil
+			}
+
+			reminders = append(reminders, model.Reminder{
+				JobID:        job.ID,
+				Title:        job.Title,
+				Company:      job.Company,
+				URL:          job.URL,
+				Status:       job.Status,
+				Since:        since.Format(time.RFC3339),
+				DaysInStatus: int(now.Sub(since).Hours() / 24),
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return reminders, nil
+}
+
+// FindDueReminders returns the stale jobs that have not been reminded about
+// for their current stay in a status yet.
+func (s *reminderService) FindDueReminders(ctx context.Context) ([]model.Reminder, error) {
+	stale, err := s.FindStaleJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []model.Reminder
+	for _, reminder := range stale {
+		sent, err := s.alreadySent(reminder)
+		if err != nil {
+			return nil, err
+		}
+		if !sent {
+			reminders = append(reminders, reminder)
+		}
+	}
+
+	return reminders, nil
+}
+
+// SendDueReminders delivers all due reminders in a single notification per
+// notifier. Reminders are marked as sent once any notifier accepts them, so
+// one broken destination does not cause the others to receive duplicates.
+// Reminders cover the server's own Notion database.
+func (s *reminderService) SendDueReminders() error {
+	reminders, err := s.FindDueReminders(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	if err := deliver(s.notifiers, reminderNotification(reminders)); err != nil {
+		return err
+	}
+
+	sentAt := time.Now().Format(time.RFC3339)
+	for _, reminder := range reminders {
+		reminder.SentAt = sentAt
+		if err := s.store.Put(reminderCollection, reminderKey(reminder), reminder); err != nil {
+			return fmt.Errorf("error recording reminder: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deliver sends notification through every notifier and succeeds if at least
+// one of them accepted it. Failures of the remaining notifiers are logged.
+func deliver(notifiers []notify.Notifier, notification model.Notification) error {
+	delivered := false
+	var errs []error
+	for _, notifier := range notifiers {
+		if err := notifier.Notify(notification); err != nil {
+			errs = append(errs, fmt.Errorf("%s notifier: %w", notifier.Name(), err))
+			continue
+		}
+		delivered = true
+	}
+
+	if !delivered {
+		return fmt.Errorf("no notifier delivered %s: %w", notification.Kind, errors.Join(errs...))
+	}
+	for _, err := range errs {
+		log.Printf("Failed to deliver %s: %v", notification.Kind, err)
+	}
+
+	return nil
+}
+
+func (s *reminderService) alreadySent(reminder model.Reminder) (bool, error) {
+	var sent model.Reminder
+	err := s.store.Get(reminderCollection, reminderKey(reminder), &sent)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reminderKey identifies one stay in a status, so a job that moves from
+// Applied to Interview can be reminded about again.
+func reminderKey(reminder model.Reminder) string {
+	return reminder.JobID + "|" + reminder.Status + "|" + reminder.Since
+}
+
+// statusSince works out when a job entered its current status, preferring the
+// recorded status history and falling back to the Notion dates for jobs
+// updated before history was kept.
+func statusSince(job model.Job) (time.Time, bool) {
+	for i := len(job.StatusHistory) - 1; i >= 0; i-- {
+		change := job.StatusHistory[i]
+		if change.Status != job.Status {
+			break
+		}
+		if i == 0 || job.StatusHistory[i-1].Status != job.Status {
+			if t, err := time.Parse(time.RFC3339, change.ChangedAt); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	for _, date := range []string{job.AppliedDate, job.CreatedDate} {
+		if date == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, date); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func reminderNotification(reminders []model.Reminder) model.Notification {
+	var text strings.Builder
+	text.WriteString("These applications have not moved in a while:\n\n")
+	for _, reminder := range reminders {
+		fmt.Fprintf(&text, "- %s at %s: %s for %d days", reminder.Title, reminder.Company, reminder.Status, reminder.DaysInStatus)
+		if reminder.URL != "" {
+			fmt.Fprintf(&text, " (%s)", reminder.URL)
+		}
+		text.WriteString("\n")
+	}
+
+	subject := fmt.Sprintf("Follow up on %d job applications", len(reminders))
+	if len(reminders) == 1 {
+		subject = fmt.Sprintf("Follow up on %s at %s", reminders[0].Title, reminders[0].Company)
+	}
+
+	return model.Notification{
+		Kind:    model.NotificationReminder,
+		Subject: subject,
+		Text:    text.String(),
+		Payload: reminders,
+	}
+}
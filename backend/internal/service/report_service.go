@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/notify"
+	"job-parser-backend/internal/report"
+	"job-parser-backend/internal/store"
+	"sort"
+	"sync"
+	"time"
+)
+
+type ReportService interface {
+	WeeklyReport(ctx context.Context) (*model.WeeklyReport, error)
+	SendWeeklyDigest() error
+	// SetSchedule replaces the schedule, from the next check on.
+	SetSchedule(schedule DigestSchedule)
+}
+
+// DigestSchedule is the weekday and hour, in server local time, after which
+// the weekly digest is sent.
+type DigestSchedule struct {
+	Weekday time.Weekday
+	Hour    int
+}
+
+type reportService struct {
+	jobService      JobService
+	reminderService ReminderService
+	store           store.Store
+	notifiers       []notify.Notifier
+
+	// mu guards schedule, which can change while the server runs.
+	mu       sync.Mutex
+	schedule DigestSchedule
+}
+
+const (
+	digestCollection = "digests"
+	topCompanyLimit  = 5
+)
+
+func NewReportService(jobService JobService, reminderService ReminderService, store store.Store, notifiers []notify.Notifier, schedule DigestSchedule) ReportService {
+	return &reportService{
+		jobService:      jobService,
+		reminderService: reminderService,
+		store:           store,
+		notifiers:       notifiers,
+		schedule:        schedule,
+	}
+}
+
+// WeeklyReport builds the digest for the last seven days. Applications are
+// counted by their Applied Date within the period; the status and company
+// counts and the streak come from GetStats and GetStreak, so they agree with
+// the dashboard.
+func (s *reportService) WeeklyReport(ctx context.Context) (*model.WeeklyReport, error) {
+	end := time.Now()
+	start := end.AddDate(0, 0, -7)
+
+	stats, err := s.jobService.GetStats(ctx, "PASTWEEK")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching stats: %w", err)
+	}
+
+	streak, err := s.jobService.GetStreak(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching streak: %w", err)
+	}
+
+	stale, err := s.reminderService.FindStaleJobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error finding stale applications: %w", err)
+	}
+
+	changes, err := s.statusChangesSince(ctx, start)
+	if err != nil {
+		return nil, fmt.Errorf("error collecting status changes: %w", err)
+	}
+
+	applied, err := s.jobService.GetAppliedJobs(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching applications: %w", err)
+	}
+	dailyCount := dailyApplications(applied, start, end)
+
+	return &model.WeeklyReport{
+		PeriodStart:       start.Format(time.RFC3339),
+		PeriodEnd:         end.Format(time.RFC3339),
+		ApplicationsSent:  len(applied),
+		DailyCount:        dailyCount,
+		StatusCount:       stats.StatusCount,
+		Streak:            *streak,
+		StatusChanges:     changes,
+		StaleApplications: stale,
+		TopCompanies:      topCompanies(stats.CompanyCount, topCompanyLimit),
+	}, nil
+}
+
+func (s *reportService) SetSchedule(schedule DigestSchedule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedule = schedule
+}
+
+// SendWeeklyDigest sends the digest once per ISO week, on or after the
+// configured weekday and hour. It is safe to call repeatedly; the scheduler
+// runs it hourly and restarts do not cause duplicate digests. Digests cover
+// the server's own Notion database.
+func (s *reportService) SendWeeklyDigest() error {
+	now := time.Now()
+
+	s.mu.Lock()
+	schedule := s.schedule
+	s.mu.Unlock()
+
+	daysSinceScheduled := (int(now.Weekday()) - int(schedule.Weekday) + 7) % 7
+	scheduled := time.Date(now.Year(), now.Month(), now.Day(), schedule.Hour, 0, 0, 0, now.Location()).AddDate(0, 0, -daysSinceScheduled)
+	if now.Before(scheduled) {
+		return nil
+	}
+
+	year, week := scheduled.ISOWeek()
+	key := fmt.Sprintf("%d-W%02d", year, week)
+
+	var sentAt string
+	err := s.store.Get(digestCollection, key, &sentAt)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+
+	weekly, err := s.WeeklyReport(context.Background())
+	if err != nil {
+		return err
+	}
+
+	html, err := report.RenderWeekly(weekly, report.FormatHTML)
+	if err != nil {
+		return err
+	}
+
+	markdown, err := report.RenderWeekly(weekly, report.FormatMarkdown)
+	if err != nil {
+		return err
+	}
+
+	notification := model.Notification{
+		Kind:    model.NotificationWeeklyDigest,
+		Subject: fmt.Sprintf("Weekly job search digest: %d applications sent", weekly.ApplicationsSent),
+		Text:    markdown,
+		HTML:    html,
+		Payload: weekly,
+	}
+
+	if err := deliver(s.notifiers, notification); err != nil {
+		return err
+	}
+
+	return s.store.Put(digestCollection, key, now.Format(time.RFC3339))
+}
+
+// statusChangesSince lists status changes recorded through UpdateJob after
+// start, newest first.
+func (s *reportService) statusChangesSince(ctx context.Context, start time.Time) ([]model.ReportStatusEntry, error) {
+	var changes []model.ReportStatusEntry
+
+	err := s.jobService.ExportJobs(ctx, "", func(job model.Job) error {
+		for _, change := range job.StatusHistory {
+			changedAt, err := time.Parse(time.RFC3339, change.ChangedAt)
+			if err != nil || changedAt.Before(start) {
+				continue
+			}
+			changes = append(changes, model.ReportStatusEntry{
+				JobID:     job.ID,
+				Title:     job.Title,
+				Company:   job.Company,
+				Status:    change.Status,
+				ChangedAt: change.ChangedAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ChangedAt > changes[j].ChangedAt })
+
+	return changes, nil
+}
+
+// dailyApplications counts jobs by the local day of their Applied Date, with
+// every day from start to end present.
+func dailyApplications(jobs []model.Job, start time.Time, end time.Time) map[string]int {
+	counts := make(map[string]int)
+	last := end.Format(time.DateOnly)
+	for day := start; day.Format(time.DateOnly) <= last; day = day.AddDate(0, 0, 1) {
+		counts[day.Format(time.DateOnly)] = 0
+	}
+
+	for _, job := range jobs {
+		if applied, err := time.Parse(time.RFC3339, job.AppliedDate); err == nil {
+			counts[applied.Local().Format(time.DateOnly)]++
+		}
+	}
+
+	return counts
+}
+
+func topCompanies(counts map[string]int, limit int) []model.CompanyCount {
+	companies := make([]model.CompanyCount, 0, len(counts))
+	for company, count := range counts {
+		companies = append(companies, model.CompanyCount{Company: company, Count: count})
+	}
+
+	sort.Slice(companies, func(i, j int) bool {
+		if companies[i].Count != companies[j].Count {
+			return companies[i].Count > companies[j].Count
+		}
+		return companies[i].Company < companies[j].Company
+	})
+
+	if len(companies) > limit {
+		companies = companies[:limit]
+	}
+
+	return companies
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/client"
+	"job-parser-backend/internal/document"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/redact"
+	"job-parser-backend/internal/store"
+	"job-parser-backend/internal/usage"
+	"job-parser-backend/internal/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrResumeNotFound = errors.New("resume not found")
+	ErrNoResume       = errors.New("no resume given and no default resume stored")
+)
+
+type ResumeService interface {
+	ParseResume(ctx context.Context, filename string, data []byte) (*model.ParsedResume, error)
+	CreateResume(ctx context.Context, name string, resume model.Resume, isDefault bool) (*model.StoredResume, error)
+	UpdateResume(ctx context.Context, id string, name string, resume *model.Resume, isDefault bool) (*model.StoredResume, error)
+	ListResumes(ctx context.Context) ([]model.ResumeSummary, error)
+	GetResume(ctx context.Context, id string) (*model.StoredResume, error)
+	GetResumeVersion(ctx context.Context, id string, version int) (*model.ResumeVersion, error)
+	ResolveResume(ctx context.Context, request model.CompareRequest) (*model.ResumeVersion, error)
+}
+
+type resumeService struct {
+	llmProvider client.LLMProvider
+	store       store.Store
+	prompts     prompt.Registry
+	redactor    redact.Redactor
+	// mu serializes writes so that moving the default flag between resumes
+	// is never observed half done.
+	mu sync.Mutex
+}
+
+// resumeCollection holds each user's resumes in a collection of their own,
+// see userCollection.
+const resumeCollection = "resumes"
+
+func NewResumeService(llmProvider client.LLMProvider, store store.Store, prompts prompt.Registry, redactor redact.Redactor) ResumeService {
+	return &resumeService{
+		llmProvider: llmProvider,
+		store:       store,
+		prompts:     prompts,
+		redactor:    redactor,
+	}
+}
+
+// ParseResume extracts the text of an uploaded PDF or DOCX resume and asks
+// the model to structure it.
+func (s *resumeService) ParseResume(ctx context.Context, filename string, data []byte) (*model.ParsedResume, error) {
+	text, err := document.ExtractText(filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	systemPrompt, err := prompt.ParseResume.Render(s.prompts, prompt.NoVars{})
+	if err != nil {
+		return nil, err
+	}
+	ctx = usage.WithOperation(ctx, systemPrompt.Name)
+
+	// The candidate's name is not known until the resume is parsed, so only
+	// the patterns are redacted here.
+	text, redaction := redactText(ctx, s.redactor, text)
+
+	messages := []map[string]string{
+		{
+			"role":    "system",
+			"content": systemPrompt.Text,
+		},
+		{
+			"role":    "user",
+			"content": text,
+		},
+	}
+
+	var resume model.Resume
+	answeredBy, err := chatJSON(ctx, s.llmProvider, model.Mixtral_Saba_24b, messages, &resume)
+	if err != nil {
+		return nil, fmt.Errorf("error structuring resume: %w", err)
+	}
+	if err := redaction.RestoreJSON(&resume); err != nil {
+		return nil, fmt.Errorf("error structuring resume: %w", err)
+	}
+
+	return &model.ParsedResume{
+		Resume:        resume,
+		Model:         answeredBy,
+		PromptVersion: systemPrompt.Version,
+	}, nil
+}
+
+// CreateResume stores a new named resume as version 1. The first resume
+// the user stores becomes their default.
+func (s *resumeService) CreateResume(ctx context.Context, name string, resume model.Resume, isDefault bool) (*model.StoredResume, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resumes, err := s.listStored(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	stored := &model.StoredResume{
+		ID:        utils.NewID(),
+		Name:      name,
+		Default:   isDefault || len(resumes) == 0,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	stored.Versions = []model.ResumeVersion{{
+		ResumeID:  stored.ID,
+		Version:   1,
+		CreatedAt: now,
+		Resume:    resume,
+	}}
+
+	if stored.Default {
+		if err := s.clearDefault(ctx, resumes); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store.Put(userCollection(ctx, resumeCollection), stored.ID, stored); err != nil {
+		return nil, fmt.Errorf("error saving resume: %w", err)
+	}
+
+	return stored, nil
+}
+
+// UpdateResume renames the resume when name is set, appends a new version
+// when resume is set, and makes it the default when isDefault is true.
+func (s *resumeService) UpdateResume(ctx context.Context, id string, name string, resume *model.Resume, isDefault bool) (*model.StoredResume, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.GetResume(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+
+	if name != "" {
+		stored.Name = name
+	}
+
+	if resume != nil {
+		stored.Versions = append(stored.Versions, model.ResumeVersion{
+			ResumeID:  stored.ID,
+			Version:   len(stored.Versions) + 1,
+			CreatedAt: now,
+			Resume:    *resume,
+		})
+	}
+
+	if isDefault && !stored.Default {
+		resumes, err := s.listStored(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.clearDefault(ctx, resumes); err != nil {
+			return nil, err
+		}
+		stored.Default = true
+	}
+
+	stored.UpdatedAt = now
+
+	if err := s.store.Put(userCollection(ctx, resumeCollection), stored.ID, stored); err != nil {
+		return nil, fmt.Errorf("error saving resume: %w", err)
+	}
+
+	return stored, nil
+}
+
+func (s *resumeService) ListResumes(ctx context.Context) ([]model.ResumeSummary, error) {
+	resumes, err := s.listStored(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]model.ResumeSummary, 0, len(resumes))
+	for _, resume := range resumes {
+		summaries = append(summaries, model.ResumeSummary{
+			ID:            resume.ID,
+			Name:          resume.Name,
+			Default:       resume.Default,
+			LatestVersion: len(resume.Versions),
+			UpdatedAt:     resume.UpdatedAt,
+		})
+	}
+
+	return summaries, nil
+}
+
+func (s *resumeService) GetResume(ctx context.Context, id string) (*model.StoredResume, error) {
+	var stored model.StoredResume
+	err := s.store.Get(userCollection(ctx, resumeCollection), id, &stored)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrResumeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading resume: %w", err)
+	}
+	return &stored, nil
+}
+
+// GetResumeVersion returns a single version of a stored resume, or the latest
+// version when version is zero.
+func (s *resumeService) GetResumeVersion(ctx context.Context, id string, version int) (*model.ResumeVersion, error) {
+	stored, err := s.GetResume(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == 0 {
+		version = len(stored.Versions)
+	}
+	if version < 1 || version > len(stored.Versions) {
+		return nil, ErrResumeNotFound
+	}
+
+	return &stored.Versions[version-1], nil
+}
+
+// ResolveResume picks the resume a compare request refers to, among those
+// of the user of ctx. Inline resumes are returned as an unsaved version with
+// an empty ResumeID.
+func (s *resumeService) ResolveResume(ctx context.Context, request model.CompareRequest) (*model.ResumeVersion, error) {
+	if request.Resume != nil {
+		return &model.ResumeVersion{Resume: *request.Resume}, nil
+	}
+
+	if request.ResumeID != "" {
+		return s.GetResumeVersion(ctx, request.ResumeID, request.ResumeVersion)
+	}
+
+	resumes, err := s.listStored(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, resume := range resumes {
+		if resume.Default {
+			return &resume.Versions[len(resume.Versions)-1], nil
+		}
+	}
+
+	return nil, ErrNoResume
+}
+
+// listStored returns the stored resumes of the user of ctx ordered by
+// creation time.
+func (s *resumeService) listStored(ctx context.Context) ([]model.StoredResume, error) {
+	records, err := s.store.List(userCollection(ctx, resumeCollection))
+	if err != nil {
+		return nil, fmt.Errorf("error listing resumes: %w", err)
+	}
+
+	resumes := make([]model.StoredResume, 0, len(records))
+	for _, raw := range records {
+		var resume model.StoredResume
+		if err := json.Unmarshal(raw, &resume); err != nil {
+			return nil, fmt.Errorf("error decoding resume: %w", err)
+		}
+		resumes = append(resumes, resume)
+	}
+
+	sort.Slice(resumes, func(i, j int) bool { return resumes[i].CreatedAt < resumes[j].CreatedAt })
+
+	return resumes, nil
+}
+
+func (s *resumeService) clearDefault(ctx context.Context, resumes []model.StoredResume) error {
+	for _, resume := range resumes {
+		if !resume.Default {
+			continue
+		}
+		resume.Default = false
+		if err := s.store.Put(userCollection(ctx, resumeCollection), resume.ID, resume); err != nil {
+			return fmt.Errorf("error saving resume: %w", err)
+		}
+	}
+	return nil
+}
+
+// resumeText flattens the parts of a resume that describe what the candidate
+// can do, for keyword matching.
+func resumeText(resume model.Resume) string {
+	parts := []string{resume.Summary, strings.Join(resume.Skills, ", ")}
+	for _, entry := range resume.Experience {
+		parts = append(parts, entry.Title)
+		parts = append(parts, entry.Bullets...)
+	}
+	for _, entry := range resume.Education {
+		parts = append(parts, entry.Degree, entry.Field)
+	}
+	for _, certification := range resume.Certifications {
+		parts = append(parts, certification.Name)
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"job-parser-backend/internal/client"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/prompt"
+	"job-parser-backend/internal/redact"
+	"job-parser-backend/internal/skills"
+	"job-parser-backend/internal/usage"
+	"job-parser-backend/internal/utils"
+	"strings"
+	"time"
+)
+
+type SuggestionService interface {
+	SuggestBulletRewrites(ctx context.Context, jobID string, request model.CompareRequest) (*model.ResumeSuggestions, error)
+}
+
+type suggestionService struct {
+	llmProvider   client.LLMProvider
+	prompts       prompt.Registry
+	redactor      redact.Redactor
+	jobService    JobService
+	resumeService ResumeService
+	skillMatcher  skills.Matcher
+}
+
+func NewSuggestionService(llmProvider client.LLMProvider, prompts prompt.Registry, redactor redact.Redactor, jobService JobService, resumeService ResumeService, skillMatcher skills.Matcher) SuggestionService {
+	return &suggestionService{
+		llmProvider:   llmProvider,
+		prompts:       prompts,
+		redactor:      redactor,
+		jobService:    jobService,
+		resumeService: resumeService,
+		skillMatcher:  skillMatcher,
+	}
+}
+
+// SuggestBulletRewrites asks the model to rewrite resume bullets so they
+// cover what the posting asks for and the resume lacks. Gaps come from the
+// keyword match and, when one is stored, the model comparison for the same
+// resume.
+func (s *suggestionService) SuggestBulletRewrites(ctx context.Context, jobID string, request model.CompareRequest) (*model.ResumeSuggestions, error) {
+	ctx = usage.WithJob(ctx, jobID)
+	job, err := s.jobService.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Description == "" {
+		return nil, ErrNoJobPosting
+	}
+
+	resume, err := s.resumeService.ResolveResume(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	gaps := s.skillMatcher.Match(resumeText(resume.Resume), job.Description).Missing
+	for _, comparison := range job.Comparisons {
+		if comparison.ResumeID == resume.ResumeID {
+			gaps = append(gaps, comparison.MissingSkills...)
+			gaps = append(gaps, comparison.ExperienceGap...)
+		}
+	}
+
+	systemPrompt, err := prompt.ResumeSuggestions.Render(s.prompts, prompt.ResumeSuggestionsVars{MissingSkills: gaps})
+	if err != nil {
+		return nil, err
+	}
+	ctx = usage.WithOperation(ctx, systemPrompt.Name)
+
+	redacted, redaction := redactResume(ctx, s.redactor, resume.Resume)
+	bytes, err := json.Marshal(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling resume: %w", err)
+	}
+
+	messages := []map[string]string{
+		{
+			"role":    "system",
+			"content": systemPrompt.Text,
+		},
+		{
+			"role":    "user",
+			"content": "Resume:\n" + string(bytes),
+		},
+		{
+			"role":    "user",
+			"content": "Job Posting:\n" + utils.DelimitPosting(job.Description),
+		},
+	}
+
+	var response struct {
+		Suggestions []model.BulletSuggestion `json:"suggestions"`
+	}
+	answeredBy, err := chatJSON(ctx, s.llmProvider, model.Gemma2_9B_Instruct, messages, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error generating resume suggestions: %w", err)
+	}
+	if err := redaction.RestoreJSON(&response); err != nil {
+		return nil, fmt.Errorf("error generating resume suggestions: %w", err)
+	}
+
+	suggestions := []model.BulletSuggestion{}
+	for _, suggestion := range response.Suggestions {
+		if suggestion.ExperienceIndex < 0 || suggestion.ExperienceIndex >= len(resume.Resume.Experience) {
+			continue
+		}
+		entry := resume.Resume.Experience[suggestion.ExperienceIndex]
+		if suggestion.BulletIndex < 0 || suggestion.BulletIndex >= len(entry.Bullets) {
+			continue
+		}
+
+		suggestion.Rewritten = strings.TrimSpace(suggestion.Rewritten)
+		suggestion.Original = entry.Bullets[suggestion.BulletIndex]
+		if suggestion.Rewritten == "" || suggestion.Rewritten == suggestion.Original {
+			continue
+		}
+
+		suggestion.Company = entry.Company
+		suggestion.Title = entry.Title
+		suggestion.Diff = utils.WordDiff(suggestion.Original, suggestion.Rewritten)
+		suggestion.DiffText = utils.FormatWordDiff(suggestion.Diff)
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return &model.ResumeSuggestions{
+		JobID:         jobID,
+		ResumeID:      resume.ResumeID,
+		ResumeVersion: resume.Version,
+		Suggestions:   suggestions,
+		Model:         answeredBy,
+		PromptVersion: systemPrompt.Version,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+	}, nil
+}
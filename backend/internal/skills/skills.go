@@ -0,0 +1,184 @@
+package skills
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed taxonomy.json
+var defaultTaxonomy []byte
+
+// Taxonomy lists the skills the matcher knows about. Aliases are matched
+// case-insensitively; Exact aliases, used for ambiguous short names such as
+// "Go" or "R", must match case as well.
+type Taxonomy struct {
+	Version string  `json:"version"`
+	Skills  []Skill `json:"skills"`
+}
+
+type Skill struct {
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Weight   int      `json:"weight"`
+	Aliases  []string `json:"aliases"`
+	Exact    []string `json:"exact"`
+}
+
+// Matcher extracts canonical skills from free text and scores how well a
+// resume covers a posting. Results depend only on the input text and the
+// taxonomy, so the same inputs always produce the same score.
+type Matcher interface {
+	Extract(text string) []string
+	Match(resumeText string, postingText string) model.SkillMatch
+}
+
+type matcher struct {
+	version  string
+	skills   []Skill
+	patterns [][]*regexp.Regexp
+	weights  map[string]int
+}
+
+// LoadTaxonomy reads a taxonomy file, or the embedded default when path is
+// empty.
+func LoadTaxonomy(path string) (*Taxonomy, error) {
+	data := defaultTaxonomy
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading skills taxonomy: %w", err)
+		}
+	}
+
+	var taxonomy Taxonomy
+	if err := json.Unmarshal(data, &taxonomy); err != nil {
+		return nil, fmt.Errorf("error decoding skills taxonomy: %w", err)
+	}
+
+	return &taxonomy, nil
+}
+
+func NewMatcher(taxonomy *Taxonomy) (Matcher, error) {
+	m := &matcher{
+		version: taxonomy.Version,
+		skills:  taxonomy.Skills,
+		weights: make(map[string]int, len(taxonomy.Skills)),
+	}
+
+	for _, skill := range taxonomy.Skills {
+		var patterns []*regexp.Regexp
+		for _, alias := range append([]string{skill.Name}, skill.Aliases...) {
+			if skill.Name == alias && len(skill.Exact) > 0 {
+				// Ambiguous names are only matched through their exact forms.
+				continue
+			}
+			pattern, err := aliasPattern(alias, false)
+			if err != nil {
+				return nil, err
+			}
+			patterns = append(patterns, pattern)
+		}
+		for _, alias := range skill.Exact {
+			pattern, err := aliasPattern(alias, true)
+			if err != nil {
+				return nil, err
+			}
+			patterns = append(patterns, pattern)
+		}
+
+		weight := skill.Weight
+		if weight < 1 {
+			weight = 1
+		}
+
+		m.patterns = append(m.patterns, patterns)
+		m.weights[skill.Name] = weight
+	}
+
+	return m, nil
+}
+
+// aliasPattern matches alias as a whole term. Letters, digits, "+" and "#"
+// count as part of a term so "Java" does not match "JavaScript" and "C" does
+// not match "C++", and so does a "." between them, so "JS" does not match
+// "Node.js" while a sentence can still end in "JS.".
+func aliasPattern(alias string, exact bool) (*regexp.Regexp, error) {
+	flags := "(?i)"
+	if exact {
+		flags = ""
+	}
+	const term = `\pL\pN+#`
+	before := `(?:^|[^` + term + `.]|(?:^|[^` + term + `])\.)`
+	after := `(?:$|[^` + term + `.]|\.(?:$|[^` + term + `]))`
+	expression := flags + before + regexp.QuoteMeta(alias) + after
+
+	pattern, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid skill alias %q: %w", alias, err)
+	}
+	return pattern, nil
+}
+
+// Extract returns the canonical names of all skills mentioned in text,
+// sorted alphabetically.
+func (m *matcher) Extract(text string) []string {
+	text = strings.Join(strings.Fields(text), " ")
+
+	found := []string{}
+	for i, skill := range m.skills {
+		for _, pattern := range m.patterns[i] {
+			if pattern.MatchString(text) {
+				found = append(found, skill.Name)
+				break
+			}
+		}
+	}
+
+	sort.Strings(found)
+	return found
+}
+
+// Match scores the share of the posting's skill weight that the resume
+// covers, from 0 to 100.
+func (m *matcher) Match(resumeText string, postingText string) model.SkillMatch {
+	resumeSkills := m.Extract(resumeText)
+	postingSkills := m.Extract(postingText)
+
+	onResume := make(map[string]bool, len(resumeSkills))
+	for _, skill := range resumeSkills {
+		onResume[skill] = true
+	}
+
+	match := model.SkillMatch{
+		ResumeSkills:    resumeSkills,
+		PostingSkills:   postingSkills,
+		Matched:         []string{},
+		Missing:         []string{},
+		TaxonomyVersion: m.version,
+	}
+
+	for _, skill := range postingSkills {
+		weight := m.weights[skill]
+		match.TotalWeight += weight
+		if onResume[skill] {
+			match.MatchedWeight += weight
+			match.Matched = append(match.Matched, skill)
+		} else {
+			match.Missing = append(match.Missing, skill)
+		}
+	}
+
+	if match.TotalWeight > 0 {
+		match.Score = int(math.Round(float64(match.MatchedWeight) * 100 / float64(match.TotalWeight)))
+	}
+
+	return match
+}
@@ -0,0 +1,176 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log is an append-only log of records kept in one JSON Lines file per day,
+// for records written on every request, such as LLM usage, which would
+// otherwise rewrite the whole store each time. Days older than the
+// retention are deleted.
+type Log interface {
+	// Append adds record to the file of the day of t.
+	Append(t time.Time, record any) error
+	// Read calls fn with each record of the days from from to to, inclusive,
+	// in the order they were appended.
+	Read(from time.Time, to time.Time, fn func(date string, raw json.RawMessage) error) error
+}
+
+type fileLog struct {
+	dir string
+	// retentionDays is how many days are kept, 0 to keep every day.
+	retentionDays int
+
+	mu sync.Mutex
+	// pruned is the date old files were last deleted on.
+	pruned string
+}
+
+const logExtension = ".jsonl"
+
+// NewFileLog keeps the log in dir, creating it on first write, and deletes
+// the files of days older than retentionDays, or none when it is 0.
+func NewFileLog(dir string, retentionDays int) Log {
+	return &fileLog{dir: dir, retentionDays: retentionDays}
+}
+
+func (l *fileLog) Append(t time.Time, record any) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding log record: %w", err)
+	}
+	date := t.Format(time.DateOnly)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(l.dir, 0o700); err != nil {
+		return fmt.Errorf("error creating log directory: %w", err)
+	}
+	if l.pruned != date {
+		if err := l.prune(t); err != nil {
+			return err
+		}
+		l.pruned = date
+	}
+
+	file, err := os.OpenFile(filepath.Join(l.dir, date+logExtension), os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+	// A line cut short by a crash is ended first, so it does not take this
+	// record with it.
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			line = append([]byte{'\n'}, line...)
+		}
+	}
+	// The line is written at once, so concurrent appends do not interleave.
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing log file: %w", err)
+	}
+	return nil
+}
+
+func (l *fileLog) Read(from time.Time, to time.Time, fn func(date string, raw json.RawMessage) error) error {
+	dates, err := l.dates()
+	if err != nil {
+		return err
+	}
+
+	first, last := from.Format(time.DateOnly), to.Format(time.DateOnly)
+	for _, date := range dates {
+		if date < first || date > last {
+			continue
+		}
+		if err := l.readDay(date, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *fileLog) readDay(date string, fn func(date string, raw json.RawMessage) error) error {
+	file, err := os.Open(filepath.Join(l.dir, date+logExtension))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading log file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// A crash mid-append can leave the last line cut short.
+		if len(line) == 0 || !json.Valid(line) {
+			continue
+		}
+		if err := fn(date, json.RawMessage(line)); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading log file for %s: %w", date, err)
+	}
+	return nil
+}
+
+// dates returns the days that have a file, in order.
+func (l *fileLog) dates() ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading log directory: %w", err)
+	}
+
+	var dates []string
+	for _, entry := range entries {
+		if date, ok := strings.CutSuffix(entry.Name(), logExtension); ok && !entry.IsDir() {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// prune deletes the files of the days before the retention counted back
+// from now. It must be called with mu held.
+func (l *fileLog) prune(now time.Time) error {
+	if l.retentionDays <= 0 {
+		return nil
+	}
+	dates, err := l.dates()
+	if err != nil {
+		return err
+	}
+
+	oldest := now.AddDate(0, 0, -l.retentionDays+1).Format(time.DateOnly)
+	for _, date := range dates {
+		if date >= oldest {
+			break
+		}
+		if err := os.Remove(filepath.Join(l.dir, date+logExtension)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error deleting old log file: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFileLog(t *testing.T) {
+	day := func(date string) time.Time {
+		parsed, err := time.ParseInLocation(time.DateOnly, date, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name      string
+		retention int
+		appends   []string
+		// truncate appends a line cut short, as by a crash, to this day,
+		// before the after records are appended.
+		truncate string
+		after    []string
+		from, to string
+		want     []string
+	}{
+		{
+			name:    "in order",
+			appends: []string{"2026-10-01", "2026-10-01", "2026-10-02"},
+			from:    "2026-10-01", to: "2026-10-02",
+			want: []string{"2026-10-01 0", "2026-10-01 1", "2026-10-02 2"},
+		},
+		{
+			name:    "within range",
+			appends: []string{"2026-09-30", "2026-10-01", "2026-10-03"},
+			from:    "2026-10-01", to: "2026-10-02",
+			want: []string{"2026-10-01 1"},
+		},
+		{
+			name:      "old days deleted",
+			retention: 2,
+			appends:   []string{"2026-10-01", "2026-10-02", "2026-10-03"},
+			from:      "2026-01-01", to: "2026-12-31",
+			want: []string{"2026-10-02 1", "2026-10-03 2"},
+		},
+		{
+			name:     "cut short line skipped",
+			appends:  []string{"2026-10-01", "2026-10-01"},
+			truncate: "2026-10-01",
+			after:    []string{"2026-10-01"},
+			from:     "2026-10-01", to: "2026-10-01",
+			want: []string{"2026-10-01 0", "2026-10-01 1", "2026-10-01 2"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "log")
+			log := NewFileLog(dir, test.retention)
+			for i, date := range test.appends {
+				if err := log.Append(day(date), i); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.truncate != "" {
+				file, err := os.OpenFile(filepath.Join(dir, test.truncate+logExtension), os.O_APPEND|os.O_WRONLY, 0o600)
+				if err != nil {
+					t.Fatal(err)
+				}
+				file.WriteString(`{"cut":`)
+				file.Close()
+			}
+			for i, date := range test.after {
+				if err := log.Append(day(date), len(test.appends)+i); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got []string
+			err := log.Read(day(test.from), day(test.to), func(date string, raw json.RawMessage) error {
+				got = append(got, date+" "+string(raw))
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Read() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNotFound is returned when a key does not exist in a collection.
+var ErrNotFound = errors.New("record not found")
+
+// Store is a small key/value document store for data that Notion cannot hold,
+// such as status history. Values are grouped into named collections and
+// encoded as JSON.
+type Store interface {
+	Get(collection string, key string, value any) error
+	Put(collection string, key string, value any) error
+	Update(collection string, key string, update func(current json.RawMessage) (any, error)) error
+	Delete(collection string, key string) error
+	List(collection string) (map[string]json.RawMessage, error)
+}
+
+type fileStore struct {
+	path string
+	mu   sync.RWMutex
+	data map[string]map[string]json.RawMessage
+}
+
+// NewFileStore opens the JSON store at path, creating it on first write.
+func NewFileStore(path string) (Store, error) {
+	s := &fileStore{
+		path: path,
+		data: make(map[string]map[string]json.RawMessage),
+	}
+
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading store file: %w", err)
+	}
+
+	if len(bytes) > 0 {
+		if err := json.Unmarshal(bytes, &s.data); err != nil {
+			return nil, fmt.Errorf("error decoding store file: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *fileStore) Get(collection string, key string, value any) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	raw, ok := s.data[collection][key]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if err := json.Unmarshal(raw, value); err != nil {
+		return fmt.Errorf("error decoding %s/%s: %w", collection, key, err)
+	}
+
+	return nil
+}
+
+func (s *fileStore) Put(collection string, key string, value any) error {
+	return s.Update(collection, key, func(json.RawMessage) (any, error) {
+		return value, nil
+	})
+}
+
+// Update replaces the value stored under key with the result of update while
+// holding the write lock, so read-modify-write cycles are not interleaved.
+// current is nil when the key does not exist yet.
+func (s *fileStore) Update(collection string, key string, update func(current json.RawMessage) (any, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, err := update(s.data[collection][key])
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error encoding %s/%s: %w", collection, key, err)
+	}
+
+	if s.data[collection] == nil {
+		s.data[collection] = make(map[string]json.RawMessage)
+	}
+	previous, existed := s.data[collection][key]
+	s.data[collection][key] = raw
+
+	if err := s.persist(); err != nil {
+		if existed {
+			s.data[collection][key] = previous
+		} else {
+			delete(s.data[collection], key)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *fileStore) Delete(collection string, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.data[collection][key]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.data[collection], key)
+
+	if err := s.persist(); err != nil {
+		s.data[collection][key] = previous
+		return err
+	}
+
+	return nil
+}
+
+func (s *fileStore) List(collection string) (map[string]json.RawMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]json.RawMessage, len(s.data[collection]))
+	for key, raw := range s.data[collection] {
+		result[key] = raw
+	}
+
+	return result, nil
+}
+
+// persist writes the store to a temporary file and renames it into place so
+// a crash mid-write never leaves a truncated store behind.
+func (s *fileStore) persist() error {
+	bytes, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("error encoding store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("error creating store directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, bytes, 0o600); err != nil {
+		return fmt.Errorf("error writing store file: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("error replacing store file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/secrets"
+)
+
+var ErrMultiUserDisabled = errors.New("per-user credentials are not enabled on this server")
+
+// profileSecret names the secret holding a user's credentials.
+func profileSecret(userID string) string {
+	return "profile/" + userID
+}
+
+// Resolver finds the credentials a request should use.
+type Resolver interface {
+	// Resolve returns the credentials sealed in token when it is not
+	// empty, else those of the authenticated user's profile, else false
+	// for the server's own credentials.
+	Resolve(ctx context.Context, token string) (Credentials, bool, error)
+	// Seal returns a token for credentials, to send with later requests.
+	Seal(credentials Credentials) (string, error)
+	// SaveProfile stores credentials for the authenticated user of ctx.
+	SaveProfile(ctx context.Context, credentials Credentials) error
+	// HasDefault reports whether the server has credentials of its own for
+	// requests that bring none.
+	HasDefault() bool
+}
+
+type resolver struct {
+	sealer     Sealer
+	secrets    secrets.Store
+	hasDefault bool
+}
+
+// NewResolver creates a resolver. A nil sealer disables credentials sent
+// with requests; profiles are kept encrypted in secretStore and need its
+// master key.
+func NewResolver(sealer Sealer, secretStore secrets.Store, hasDefault bool) Resolver {
+	return &resolver{sealer: sealer, secrets: secretStore, hasDefault: hasDefault}
+}
+
+func (r *resolver) Resolve(ctx context.Context, token string) (Credentials, bool, error) {
+	if token != "" {
+		if r.sealer == nil {
+			return Credentials{}, false, ErrMultiUserDisabled
+		}
+		credentials, err := r.sealer.Open(token)
+		if err != nil {
+			return Credentials{}, false, err
+		}
+		return credentials, true, nil
+	}
+
+	userID := User(ctx)
+	if userID == "" {
+		return Credentials{}, false, nil
+	}
+
+	value, err := r.secrets.Get(profileSecret(userID))
+	if errors.Is(err, secrets.ErrNotFound) {
+		return Credentials{}, false, nil
+	}
+	if err != nil {
+		return Credentials{}, false, fmt.Errorf("error reading profile of %s: %w", userID, err)
+	}
+
+	var credentials Credentials
+	if err := json.Unmarshal([]byte(value), &credentials); err != nil {
+		return Credentials{}, false, fmt.Errorf("error decoding profile of %s: %w", userID, err)
+	}
+	return credentials, true, nil
+}
+
+func (r *resolver) Seal(credentials Credentials) (string, error) {
+	if r.sealer == nil {
+		return "", ErrMultiUserDisabled
+	}
+	if err := credentials.Validate(); err != nil {
+		return "", err
+	}
+	return r.sealer.Seal(credentials)
+}
+
+func (r *resolver) SaveProfile(ctx context.Context, credentials Credentials) error {
+	userID := User(ctx)
+	if userID == "" {
+		return ErrNoCredentials
+	}
+	if err := credentials.Validate(); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(credentials)
+	if err != nil {
+		return fmt.Errorf("error encoding profile: %w", err)
+	}
+
+	err = r.secrets.Put(profileSecret(userID), string(value))
+	if errors.Is(err, secrets.ErrNoMasterKey) {
+		return ErrMultiUserDisabled
+	}
+	return err
+}
+
+func (r *resolver) HasDefault() bool {
+	return r.hasDefault
+}
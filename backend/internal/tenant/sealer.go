@@ -0,0 +1,78 @@
+package tenant
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidToken = errors.New("invalid credentials token")
+
+// Sealer encrypts credentials with a server key, so clients can hold their
+// keys as an opaque token and send it with each request instead of the
+// server storing them.
+type Sealer interface {
+	Seal(credentials Credentials) (string, error)
+	Open(token string) (Credentials, error)
+}
+
+type sealer struct {
+	aead cipher.AEAD
+}
+
+// NewSealer creates a sealer from a base64-encoded 32-byte AES-256 key.
+func NewSealer(key string) (Sealer, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("credentials key must be 32 bytes, base64 encoded")
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credentials cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credentials cipher: %w", err)
+	}
+
+	return &sealer{aead: aead}, nil
+}
+
+func (s *sealer) Seal(credentials Credentials) (string, error) {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return "", fmt.Errorf("error encoding credentials: %w", err)
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	sealed := s.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (s *sealer) Open(token string) (Credentials, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return Credentials{}, ErrInvalidToken
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return Credentials{}, ErrInvalidToken
+	}
+
+	var credentials Credentials
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return Credentials{}, ErrInvalidToken
+	}
+	return credentials, nil
+}
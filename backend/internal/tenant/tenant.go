@@ -0,0 +1,74 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"job-parser-backend/internal/utils"
+)
+
+var (
+	ErrNoCredentials      = errors.New("no credentials for this request")
+	ErrInvalidCredentials = errors.New("notionApiKey, notionDatabaseId and groqApiKey are required")
+)
+
+// Credentials are one user's keys for the services the backend calls on
+// their behalf.
+type Credentials struct {
+	NotionAPIKey     string `json:"notionApiKey"`
+	NotionDatabaseID string `json:"notionDatabaseId"`
+	GroqAPIKey       string `json:"groqApiKey"`
+}
+
+func (c Credentials) Validate() error {
+	if c.NotionAPIKey == "" || c.NotionDatabaseID == "" || c.GroqAPIKey == "" {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// Fingerprint identifies the credentials without revealing them, to key
+// cached clients and to tell anonymous users apart.
+func (c Credentials) Fingerprint() string {
+	return utils.ContentHash(c.NotionAPIKey + "\x00" + c.NotionDatabaseID + "\x00" + c.GroqAPIKey)[:16]
+}
+
+type credentialsKey struct{}
+
+type userKey struct{}
+
+// WithCredentials makes the calls made with ctx use credentials instead of
+// the server's own.
+func WithCredentials(ctx context.Context, credentials Credentials) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, credentials)
+}
+
+// CredentialsFrom returns the credentials of ctx, or false when the
+// server's own apply.
+func CredentialsFrom(ctx context.Context) (Credentials, bool) {
+	credentials, ok := ctx.Value(credentialsKey{}).(Credentials)
+	return credentials, ok
+}
+
+// WithUser records the authenticated user making the request.
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// User returns the authenticated user of ctx, or "".
+func User(ctx context.Context) string {
+	userID, _ := ctx.Value(userKey{}).(string)
+	return userID
+}
+
+// Key is who usage and quotas are attributed to: the authenticated user,
+// else the fingerprint of the request's credentials, else "" for the
+// server's own credentials.
+func Key(ctx context.Context) string {
+	if userID := User(ctx); userID != "" {
+		return userID
+	}
+	if credentials, ok := CredentialsFrom(ctx); ok {
+		return "anonymous:" + credentials.Fingerprint()
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package tokens
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:embed limits.json
+var defaultLimits []byte
+
+// ModelLimits lists the context window of each model. Models not listed use
+// Default.
+type ModelLimits struct {
+	Default Limits            `json:"default"`
+	Models  map[string]Limits `json:"models"`
+}
+
+// Limits describe one model. OutputTokens is reserved for the reply, and
+// CharsPerToken is the average the tokenizer produces on English text.
+type Limits struct {
+	ContextTokens int     `json:"contextTokens"`
+	OutputTokens  int     `json:"outputTokens"`
+	CharsPerToken float64 `json:"charsPerToken"`
+}
+
+// messageOverhead approximates the tokens each chat message adds for its
+// role and separators.
+const messageOverhead = 8
+
+// Estimator approximates token counts without the model's tokenizer. The
+// estimates are deliberately rough, so budgets keep a margin.
+type Estimator interface {
+	Estimate(model string, text string) int
+	// InputBudget is how many tokens of input fit alongside the reply and
+	// the given messages, such as the system prompt.
+	InputBudget(model string, messages []map[string]string) int
+	// Chunk splits text into pieces of at most maxTokens, breaking between
+	// lines where possible and between words otherwise.
+	Chunk(model string, text string, maxTokens int) []string
+}
+
+type estimator struct {
+	limits ModelLimits
+}
+
+// LoadLimits reads a limits file, or the embedded default when path is
+// empty.
+func LoadLimits(path string) (*ModelLimits, error) {
+	data := defaultLimits
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading token limits: %w", err)
+		}
+	}
+
+	var limits ModelLimits
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return nil, fmt.Errorf("error decoding token limits: %w", err)
+	}
+
+	for name, l := range limits.Models {
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("invalid token limits for %s: %w", name, err)
+		}
+	}
+	if err := limits.Default.validate(); err != nil {
+		return nil, fmt.Errorf("invalid default token limits: %w", err)
+	}
+
+	return &limits, nil
+}
+
+func (l Limits) validate() error {
+	if l.ContextTokens <= l.OutputTokens || l.OutputTokens <= 0 {
+		return fmt.Errorf("contextTokens must be larger than a positive outputTokens")
+	}
+	if l.CharsPerToken <= 0 {
+		return fmt.Errorf("charsPerToken must be positive")
+	}
+	return nil
+}
+
+func NewEstimator(limits *ModelLimits) Estimator {
+	return &estimator{limits: *limits}
+}
+
+func (e *estimator) limitsFor(model string) Limits {
+	if l, ok := e.limits.Models[model]; ok {
+		return l
+	}
+	return e.limits.Default
+}
+
+func (e *estimator) Estimate(model string, text string) int {
+	return int(math.Ceil(float64(utf8.RuneCountInString(text)) / e.limitsFor(model).CharsPerToken))
+}
+
+func (e *estimator) InputBudget(model string, messages []map[string]string) int {
+	l := e.limitsFor(model)
+	budget := l.ContextTokens - l.OutputTokens - messageOverhead
+	for _, message := range messages {
+		budget -= e.Estimate(model, message["content"]) + messageOverhead
+	}
+	return max(budget, 0)
+}
+
+func (e *estimator) Chunk(model string, text string, maxTokens int) []string {
+	var chunks []string
+	var current strings.Builder
+	currentTokens := 0
+
+	flush := func() {
+		if chunk := strings.TrimSpace(current.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+		currentTokens = 0
+	}
+
+	add := func(piece string, separator string) {
+		tokens := e.Estimate(model, piece+separator)
+		if currentTokens+tokens > maxTokens {
+			flush()
+		}
+		current.WriteString(piece)
+		current.WriteString(separator)
+		currentTokens += tokens
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if e.Estimate(model, line+"\n") <= maxTokens {
+			add(line, "\n")
+			continue
+		}
+		for _, word := range strings.Fields(line) {
+			add(word, " ")
+		}
+		current.WriteString("\n")
+	}
+	flush()
+
+	return chunks
+}
@@ -0,0 +1,77 @@
+package usage
+
+import (
+	"context"
+	"job-parser-backend/internal/model"
+	"log"
+	"sync"
+)
+
+type operationKey struct{}
+
+type jobKey struct{}
+
+type pendingKey struct{}
+
+// pending holds the records of LLM calls made before their job was saved.
+type pending struct {
+	mu      sync.Mutex
+	meter   *meter
+	records []model.UsageRecord
+}
+
+// WithOperation labels the LLM calls made with ctx, e.g. "extract_job".
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// WithJob attributes the LLM calls made with ctx to a saved job.
+func WithJob(ctx context.Context, jobID string) context.Context {
+	return context.WithValue(ctx, jobKey{}, jobID)
+}
+
+// Operation returns the label set by WithOperation, or "".
+func Operation(ctx context.Context) string {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	return operation
+}
+
+// Job returns the job set by WithJob, or "".
+func Job(ctx context.Context) string {
+	jobID, _ := ctx.Value(jobKey{}).(string)
+	return jobID
+}
+
+// WithPendingJob holds back the records of the LLM calls made with ctx until
+// the returned function attributes them to a job, for calls made before the
+// job is saved and has an ID. The function must be called once the job is
+// saved or has failed to, with "" in that case, or the calls go unrecorded.
+func WithPendingJob(ctx context.Context) (context.Context, func(jobID string)) {
+	batch := &pending{}
+	attribute := func(jobID string) {
+		batch.mu.Lock()
+		defer batch.mu.Unlock()
+		for _, record := range batch.records {
+			record.JobID = jobID
+			if err := batch.meter.record(record); err != nil {
+				log.Printf("Failed to record LLM usage: %v", err)
+			}
+		}
+		batch.records = nil
+	}
+	return context.WithValue(ctx, pendingKey{}, batch), attribute
+}
+
+// hold keeps record for WithPendingJob if ctx has a pending job, and
+// reports whether it did.
+func hold(ctx context.Context, m *meter, record model.UsageRecord) bool {
+	batch, ok := ctx.Value(pendingKey{}).(*pending)
+	if !ok {
+		return false
+	}
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+	batch.meter = m
+	batch.records = append(batch.records, record)
+	return true
+}
@@ -0,0 +1,341 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-parser-backend/internal/client"
+	"job-parser-backend/internal/model"
+	"job-parser-backend/internal/store"
+	"job-parser-backend/internal/tenant"
+	"log"
+	"maps"
+	"math"
+	"sync"
+	"time"
+)
+
+var ErrBudgetExceeded = errors.New("monthly LLM budget exceeded")
+
+const (
+	// BudgetRefuse fails LLM calls once the budget is spent.
+	BudgetRefuse = "refuse"
+	// BudgetDowngrade switches to each model's DowngradeTo model once the
+	// budget is spent, and refuses models that have none.
+	BudgetDowngrade = "downgrade"
+)
+
+// Budget is a monthly spending limit in the price table's currency, applied
+// to each user separately. A zero Monthly means no limit.
+type Budget struct {
+	Monthly float64
+	Action  string
+}
+
+// Meter records the tokens, latency and cost of every call made through the
+// providers it wraps, and enforces the monthly budget of each user across
+// all of them.
+type Meter interface {
+	Wrap(provider client.LLMProvider) client.LLMProvider
+	Report(ctx context.Context, from time.Time, to time.Time, jobID string) (*model.UsageReport, error)
+	// SetBudget replaces the budget, from the next call on.
+	SetBudget(budget Budget)
+}
+
+type meter struct {
+	records store.Log
+	prices  *PriceTable
+
+	// mu guards the budget, which can change while the server runs, and the
+	// running totals of each user for month, loaded from the store on the
+	// first call of each month.
+	mu     sync.Mutex
+	budget Budget
+	month  string
+	spent  map[string]float64
+}
+
+const (
+	dateFormat  = time.DateOnly
+	monthFormat = "2006-01"
+)
+
+// NewMeter creates a meter that appends each call's usage to records.
+func NewMeter(records store.Log, prices *PriceTable, budget Budget) Meter {
+	return &meter{
+		records: records,
+		prices:  prices,
+		budget:  budget,
+	}
+}
+
+func (m *meter) SetBudget(budget Budget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.budget = budget
+}
+
+func (m *meter) currentBudget() Budget {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.budget
+}
+
+func (m *meter) Wrap(provider client.LLMProvider) client.LLMProvider {
+	return &meteredProvider{meter: m, provider: provider}
+}
+
+type meteredProvider struct {
+	meter    *meter
+	provider client.LLMProvider
+}
+
+func (p *meteredProvider) Name() string {
+	return p.provider.Name()
+}
+
+func (p *meteredProvider) ChatCompletion(ctx context.Context, body map[string]any) (*model.ChatResponse, error) {
+	m := p.meter
+	requested, _ := body["model"].(string)
+	user := tenant.Key(ctx)
+	modelName, err := m.allow(user, requested)
+	if err != nil {
+		return nil, err
+	}
+	if modelName != requested {
+		body = maps.Clone(body)
+		body["model"] = modelName
+	}
+
+	response, err := p.provider.ChatCompletion(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	// Providers may report a dated snapshot name; callers and the price
+	// table know the model by the name it was requested as.
+	response.Model = modelName
+
+	record := model.UsageRecord{
+		Time:             time.Now().Format(time.RFC3339),
+		User:             user,
+		Operation:        Operation(ctx),
+		JobID:            Job(ctx),
+		Provider:         p.provider.Name(),
+		Model:            modelName,
+		PromptTokens:     response.Usage.PromptTokens,
+		CompletionTokens: response.Usage.CompletionTokens,
+		LatencyMs:        response.Latency.Milliseconds(),
+		Cost:             m.prices.Cost(modelName, response.Usage),
+	}
+	if modelName != requested {
+		record.RequestedModel = requested
+	}
+
+	if hold(ctx, m, record) {
+		return response, nil
+	}
+
+	// The call has been paid for either way, so a failure to record it must
+	// not fail the request.
+	if err := m.record(record); err != nil {
+		log.Printf("Failed to record LLM usage: %v", err)
+	}
+
+	return response, nil
+}
+
+// allow returns the model to call instead of requested, which differs only
+// when user's budget is spent and the action is to downgrade.
+func (m *meter) allow(user string, requested string) (string, error) {
+	budget := m.currentBudget()
+	if budget.Monthly <= 0 {
+		return requested, nil
+	}
+
+	spent, err := m.monthSpend(time.Now(), user)
+	if err != nil {
+		return "", err
+	}
+	if spent < budget.Monthly {
+		return requested, nil
+	}
+
+	if budget.Action == BudgetDowngrade {
+		if cheaper := m.prices.Models[requested].DowngradeTo; cheaper != "" {
+			return cheaper, nil
+		}
+	}
+	return "", fmt.Errorf("%w: spent %.2f of %.2f %s", ErrBudgetExceeded, spent, budget.Monthly, m.prices.Currency)
+}
+
+func (m *meter) monthSpend(now time.Time, user string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	month := now.Format(monthFormat)
+	if m.month == month {
+		return m.spent[user], nil
+	}
+
+	spent := map[string]float64{}
+	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	err := m.records.Read(firstDay, now, func(date string, raw json.RawMessage) error {
+		var record model.UsageRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return fmt.Errorf("error decoding LLM usage for %s: %w", date, err)
+		}
+		spent[record.User] += record.Cost
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error reading LLM usage: %w", err)
+	}
+
+	m.month, m.spent = month, spent
+	return spent[user], nil
+}
+
+func (m *meter) record(record model.UsageRecord) error {
+	now := time.Now()
+	if m.currentBudget().Monthly > 0 {
+		if _, err := m.monthSpend(now, record.User); err != nil {
+			return err
+		}
+	}
+
+	if err := m.records.Append(now, record); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	if m.month == now.Format(monthFormat) {
+		m.spent[record.User] += record.Cost
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// Report totals the usage of the user of ctx per day from from to to,
+// optionally for one job. The budget is always for the current month.
+func (m *meter) Report(ctx context.Context, from time.Time, to time.Time, jobID string) (*model.UsageReport, error) {
+	user := tenant.Key(ctx)
+	report := &model.UsageReport{
+		From:     from.Format(dateFormat),
+		To:       to.Format(dateFormat),
+		JobID:    jobID,
+		Currency: m.prices.Currency,
+		Days:     []model.UsageDay{},
+	}
+
+	// Days are read in order, so each starts a new entry.
+	var dates []string
+	days := map[string][]model.UsageRecord{}
+	err := m.records.Read(from, to, func(date string, raw json.RawMessage) error {
+		var record model.UsageRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return fmt.Errorf("error decoding LLM usage for %s: %w", date, err)
+		}
+		if _, ok := days[date]; !ok {
+			dates = append(dates, date)
+		}
+		days[date] = append(days[date], record)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading LLM usage: %w", err)
+	}
+
+	var total totals
+	for _, date := range dates {
+		records := days[date]
+
+		var day totals
+		byOperation := map[string]*totals{}
+		byModel := map[string]*totals{}
+		for _, record := range records {
+			if record.User != user || (jobID != "" && record.JobID != jobID) {
+				continue
+			}
+			day.add(record)
+			total.add(record)
+			group(byOperation, record.Operation).add(record)
+			group(byModel, record.Model).add(record)
+		}
+		if day.Calls == 0 {
+			continue
+		}
+
+		report.Days = append(report.Days, model.UsageDay{
+			Date:        date,
+			UsageTotals: day.result(),
+			ByOperation: results(byOperation),
+			ByModel:     results(byModel),
+		})
+	}
+	report.Total = total.result()
+
+	if budget := m.currentBudget(); budget.Monthly > 0 {
+		now := time.Now()
+		spent, err := m.monthSpend(now, user)
+		if err != nil {
+			return nil, err
+		}
+		report.Budget = &model.UsageBudget{
+			Month:     now.Format(monthFormat),
+			Limit:     budget.Monthly,
+			Spent:     round(spent),
+			Remaining: round(math.Max(budget.Monthly-spent, 0)),
+			Action:    budget.Action,
+			Exceeded:  spent >= budget.Monthly,
+		}
+	}
+
+	return report, nil
+}
+
+// totals accumulates records; latency is summed and averaged on output.
+type totals struct {
+	model.UsageTotals
+	latencyMs int64
+}
+
+func (t *totals) add(record model.UsageRecord) {
+	t.Calls++
+	t.PromptTokens += record.PromptTokens
+	t.CompletionTokens += record.CompletionTokens
+	t.Cost += record.Cost
+	t.latencyMs += record.LatencyMs
+}
+
+func (t *totals) result() model.UsageTotals {
+	result := t.UsageTotals
+	result.Cost = round(result.Cost)
+	if result.Calls > 0 {
+		result.AvgLatencyMs = t.latencyMs / int64(result.Calls)
+	}
+	return result
+}
+
+func group(groups map[string]*totals, key string) *totals {
+	if key == "" {
+		key = "other"
+	}
+	if groups[key] == nil {
+		groups[key] = &totals{}
+	}
+	return groups[key]
+}
+
+func results(groups map[string]*totals) map[string]model.UsageTotals {
+	out := make(map[string]model.UsageTotals, len(groups))
+	for key, t := range groups {
+		out[key] = t.result()
+	}
+	return out
+}
+
+// round keeps costs to a millionth, enough for per-call prices.
+func round(cost float64) float64 {
+	return math.Round(cost*1e6) / 1e6
+}
@@ -0,0 +1,53 @@
+package usage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"job-parser-backend/internal/model"
+	"os"
+)
+
+//go:embed prices.json
+var defaultPrices []byte
+
+// PriceTable is the per-model rate card. Models missing from it are
+// recorded at no cost.
+type PriceTable struct {
+	Currency string           `json:"currency"`
+	Models   map[string]Price `json:"models"`
+}
+
+// Price is in Currency per million tokens. DowngradeTo names the cheaper
+// model used instead once the monthly budget is spent.
+type Price struct {
+	InputPerMillion  float64 `json:"inputPerMillion"`
+	OutputPerMillion float64 `json:"outputPerMillion"`
+	DowngradeTo      string  `json:"downgradeTo,omitempty"`
+}
+
+// LoadPrices reads a price table file, or the embedded default when path is
+// empty.
+func LoadPrices(path string) (*PriceTable, error) {
+	data := defaultPrices
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading LLM prices: %w", err)
+		}
+	}
+
+	var prices PriceTable
+	if err := json.Unmarshal(data, &prices); err != nil {
+		return nil, fmt.Errorf("error decoding LLM prices: %w", err)
+	}
+
+	return &prices, nil
+}
+
+// Cost prices the tokens of one call.
+func (t *PriceTable) Cost(modelName string, tokens model.ChatUsage) float64 {
+	price := t.Models[modelName]
+	return (float64(tokens.PromptTokens)*price.InputPerMillion + float64(tokens.CompletionTokens)*price.OutputPerMillion) / 1e6
+}
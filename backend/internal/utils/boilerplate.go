@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// boilerplate matches legal and HR text that postings append to every job
+// and that carries nothing about the role itself.
+var boilerplate = regexp.MustCompile(`(?i)` + strings.Join([]string{
+	`equal (employment )?opportunit(y|ies)`,
+	`\bEEO\b`,
+	`affirmative action`,
+	`without regard to (race|colou?r|religion|sex|gender|age|national origin)`,
+	`protected veteran`,
+	`reasonable accommodations?`,
+	`accommodations are available`,
+	`\bE-Verify\b`,
+	`pay transparency`,
+	`know your rights`,
+	`(applicant|candidate) privacy (notice|policy)`,
+	`unsolicited (resumes|cvs|applications)`,
+	`recruitment agencies`,
+	`all rights reserved`,
+}, "|"))
+
+// StripBoilerplate removes equal opportunity statements, accommodation and
+// privacy notices and similar paragraphs from a job posting. A paragraph
+// that is mostly boilerplate is removed whole. From any other paragraph
+// only the matching sentences are removed, so a benefits paragraph that ends
+// in "We are an equal opportunity employer." keeps the benefits.
+func StripBoilerplate(posting string) string {
+	blocks := strings.Split(strings.ReplaceAll(posting, "\r\n", "\n"), "\n\n")
+
+	kept := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		if !boilerplate.MatchString(block) {
+			kept = append(kept, block)
+			continue
+		}
+
+		var lines []string
+		matched, total := 0, 0
+		for _, line := range strings.Split(block, "\n") {
+			var rest []string
+			for _, sentence := range sentences(line) {
+				total += len(sentence)
+				if boilerplate.MatchString(sentence) {
+					matched += len(sentence)
+				} else {
+					rest = append(rest, sentence)
+				}
+			}
+			if len(rest) > 0 {
+				lines = append(lines, strings.Join(rest, " "))
+			}
+		}
+		if matched*2 >= total || len(lines) == 0 {
+			continue
+		}
+		kept = append(kept, strings.Join(lines, "\n"))
+	}
+
+	return strings.TrimSpace(strings.Join(kept, "\n\n"))
+}
+
+// sentences splits line after each ".", "!" or "?" followed by a space.
+func sentences(line string) []string {
+	var parts []string
+	for {
+		end := strings.IndexFunc(line, func(r rune) bool { return r == '.' || r == '!' || r == '?' })
+		for end >= 0 && end+1 < len(line) && line[end+1] != ' ' {
+			next := strings.IndexAny(line[end+1:], ".!?")
+			if next < 0 {
+				end = -1
+				break
+			}
+			end += 1 + next
+		}
+		if end < 0 || end+1 >= len(line) {
+			break
+		}
+		if part := strings.TrimSpace(line[:end+1]); part != "" {
+			parts = append(parts, part)
+		}
+		line = line[end+2:]
+	}
+	if part := strings.TrimSpace(line); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
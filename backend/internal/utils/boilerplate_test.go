@@ -0,0 +1,49 @@
+package utils
+
+import "testing"
+
+func TestStripBoilerplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		posting string
+		want    string
+	}{
+		{
+			name:    "no boilerplate",
+			posting: "We build payment APIs in Go.\n\nYou have 3+ years of backend experience.",
+			want:    "We build payment APIs in Go.\n\nYou have 3+ years of backend experience.",
+		},
+		{
+			name: "boilerplate paragraph",
+			posting: "We build payment APIs in Go.\n\n" +
+				"Acme is an equal opportunity employer. We consider all applicants without regard to race, religion or age.",
+			want: "We build payment APIs in Go.",
+		},
+		{
+			name: "mixed paragraph",
+			posting: "Benefits include health insurance, a 401(k) match and 25 days of paid leave. " +
+				"You will get a yearly learning budget of $2,000. We are an equal opportunity employer.",
+			want: "Benefits include health insurance, a 401(k) match and 25 days of paid leave. " +
+				"You will get a yearly learning budget of $2,000.",
+		},
+		{
+			name: "mixed lines",
+			posting: "Requirements:\n- 5 years of Kubernetes\n- Experience with Terraform\n" +
+				"Reasonable accommodations are available on request.",
+			want: "Requirements:\n- 5 years of Kubernetes\n- Experience with Terraform",
+		},
+		{
+			name:    "mostly boilerplate paragraph",
+			posting: "Apply now!\n\nWe are an EEO employer and participate in E-Verify. Apply today.",
+			want:    "Apply now!",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := StripBoilerplate(test.posting); got != test.want {
+				t.Errorf("StripBoilerplate() =\n%q\nwant\n%q", got, test.want)
+			}
+		})
+	}
+}
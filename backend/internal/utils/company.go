@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// companySuffixes are legal-form words ignored when comparing company names.
+var companySuffixes = map[string]bool{
+	"inc": true, "llc": true, "ltd": true, "limited": true, "corp": true, "corporation": true,
+	"co": true, "gmbh": true, "ag": true, "plc": true, "bv": true, "sa": true,
+}
+
+// words lowercases s and reduces it to its words.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+}
+
+// CompanyName normalizes a company name for comparison: lowercase words
+// without punctuation or a trailing legal form such as Inc or GmbH.
+func CompanyName(s string) string {
+	name := words(s)
+	for len(name) > 1 && companySuffixes[name[len(name)-1]] {
+		name = name[:len(name)-1]
+	}
+	return strings.Join(name, " ")
+}
+
+// CompanyInSource reports whether company is named in the posting text or
+// its URL. Text must contain the name as whole words; URLs often run words
+// together, as in acme-corp.com or jobs.acmecorp.io, so there the name
+// only has to appear without its spaces.
+func CompanyInSource(company string, posting string, url string) bool {
+	name := CompanyName(company)
+	if name == "" {
+		return true
+	}
+
+	if strings.Contains(" "+strings.Join(words(posting), " ")+" ", " "+name+" ") {
+		return true
+	}
+
+	compact := strings.ReplaceAll(name, " ", "")
+	return len(compact) >= 3 && strings.Contains(strings.Join(words(url), ""), compact)
+}
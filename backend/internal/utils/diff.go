@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"job-parser-backend/internal/model"
+	"strings"
+)
+
+// WordDiff compares two sentences word by word using the longest common
+// subsequence and returns the merged runs of equal, deleted and inserted
+// words.
+func WordDiff(original string, rewritten string) []model.DiffSegment {
+	a := strings.Fields(original)
+	b := strings.Fields(rewritten)
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var segments []model.DiffSegment
+	add := func(op string, word string) {
+		if n := len(segments); n > 0 && segments[n-1].Op == op {
+			segments[n-1].Text += " " + word
+			return
+		}
+		segments = append(segments, model.DiffSegment{Op: op, Text: word})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(model.DiffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(model.DiffDelete, a[i])
+			i++
+		default:
+			add(model.DiffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(model.DiffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(model.DiffInsert, b[j])
+	}
+
+	return segments
+}
+
+// FormatWordDiff renders segments in the style of git's word diff, e.g.
+// "Built [-services-]{+Go microservices+} on AWS".
+func FormatWordDiff(segments []model.DiffSegment) string {
+	parts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		switch segment.Op {
+		case model.DiffDelete:
+			parts = append(parts, "[-"+segment.Text+"-]")
+		case model.DiffInsert:
+			parts = append(parts, "{+"+segment.Text+"+}")
+		default:
+			parts = append(parts, segment.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading  = regexp.MustCompile(`(?m)^#{1,6}\s+`)
+	markdownEmphasis = regexp.MustCompile(`\*{1,2}([^*\n]+)\*{1,2}`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	markdownBullet   = regexp.MustCompile(`(?m)^\s*[*+]\s+`)
+)
+
+// MarkdownToText strips the Markdown generated for letters and reports down
+// to plain text, keeping link targets and turning bullets into dashes.
+func MarkdownToText(markdown string) string {
+	text := markdownHeading.ReplaceAllString(markdown, "")
+	text = markdownLink.ReplaceAllString(text, "$1 ($2)")
+	text = markdownBullet.ReplaceAllString(text, "- ")
+	text = markdownEmphasis.ReplaceAllString(text, "$1")
+	return strings.TrimSpace(text)
+}
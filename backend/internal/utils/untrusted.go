@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// postingTag delimits posting text in prompts. The prompts tell the model
+// that everything between the tags is data, not instructions.
+const postingTag = "job_posting"
+
+var sentenceBreak = regexp.MustCompile(`[.!?]\s+|\n+`)
+
+var postingTags = regexp.MustCompile(`(?i)<\s*/?\s*` + postingTag + `\s*>`)
+
+// injection matches text aimed at a language model rather than at a
+// candidate: attempts to override instructions, chat template markers and
+// our own output fields.
+var injection = regexp.MustCompile(`(?im)` + strings.Join([]string{
+	`\b(ignore|disregard|forget|override)\s+(all\s+|any\s+)?(of\s+)?(the\s+|your\s+)?(previous|prior|above|earlier|preceding|system)\s+(instructions|prompts?|directions|rules)`,
+	`\byou\s+are\s+now\s+(a|an|the)\b`,
+	`\b(new|updated|system)\s+(instructions|prompt)\s*:`,
+	`\b(note|message|instructions?)\s+(to|for)\s+(the\s+)?(ai|llm|assistant|model|chatgpt|gpt|language\s+model)\b`,
+	`\b(output|return|set|respond\s+with|reply\s+with)\s+(the\s+)?"?(company|title|country|match\s?score)"?\s*(:|=|to|as)`,
+	`"(matchScore|missingSkills|recommendations)"\s*:`,
+	`<\|?\s*(im_start|im_end|system|endoftext)\s*\|?>`,
+	`\[/?INST\]`,
+	`<<\s*/?SYS\s*>>`,
+	`^\s*(system|assistant)\s*:`,
+	`</?\s*` + postingTag + `\s*>`,
+}, "|"))
+
+// SanitizePosting removes characters that hide text from a human reader but
+// not from a model: zero-width and bidirectional control characters and
+// other non-printing control characters except newlines and tabs.
+func SanitizePosting(posting string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return r
+		case r == '\r':
+			return -1
+		case unicode.Is(unicode.Cf, r), unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, posting)
+}
+
+// DelimitPosting wraps untrusted posting text in tags for a prompt. Tags
+// inside the text are defused so it cannot close the block early.
+func DelimitPosting(posting string) string {
+	posting = postingTags.ReplaceAllString(SanitizePosting(posting), "[job posting]")
+	return "<" + postingTag + ">\n" + strings.TrimSpace(posting) + "\n</" + postingTag + ">"
+}
+
+// SanitizeField cleans a short value taken from a posting, such as a title,
+// before it is used inside a prompt: one line, at most maxRunes long.
+func SanitizeField(value string, maxRunes int) string {
+	value = strings.Join(strings.Fields(SanitizePosting(value)), " ")
+	if runes := []rune(value); len(runes) > maxRunes {
+		value = string(runes[:maxRunes])
+	}
+	return value
+}
+
+// DetectInjection returns the passages of a posting that look like
+// instructions to a language model, or nil when there are none.
+func DetectInjection(posting string) []string {
+	return injection.FindAllString(SanitizePosting(posting), 5)
+}
+
+// WithoutInjection returns the posting without the sentences DetectInjection
+// flags, which is the text facts about the job should come from.
+func WithoutInjection(posting string) string {
+	var kept []string
+	for _, sentence := range sentenceBreak.Split(SanitizePosting(posting), -1) {
+		if !injection.MatchString(sentence) {
+			kept = append(kept, sentence)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -1,16 +1,48 @@
 import { STORAGEKEY } from '../utils/constants.js'
 import { getStorageValue } from '../utils/utils.js' // Always add .js to import path
 
-const fetchWrapper = async (url, options = {}) => {
-  let baseURL = await getStorageValue(STORAGEKEY.baseURL)
+const getBaseURL = async () => {
+  const baseURL = await getStorageValue(STORAGEKEY.baseURL)
   if (!baseURL) throw new Error('Base URL is not set')
-  baseURL = baseURL.replace(/\/$/, '') + '/api/job/'
+  return baseURL.replace(/\/$/, '')
+}
+
+const fetchWrapper = async (url, options = {}) => {
+  const baseURL = (await getBaseURL()) + '/api/job/'
   if (!url.startsWith('/')) url = url.replace(/^\//, '')
 
+  // The server refuses requests without a key created with `server keys create`
+  const apiKey = await getStorageValue(STORAGEKEY.apiKey)
+  if (apiKey) options = { ...options, headers: { ...options.headers, 'X-API-Key': apiKey } }
+
+  // Requests run with the user's own Notion and Groq keys when they saved some
+  const credentialsToken = await getStorageValue(STORAGEKEY.credentialsToken)
+  if (credentialsToken) {
+    options = { ...options, headers: { ...options.headers, 'X-Credentials': credentialsToken } }
+  }
+
   const response = await fetch(baseURL + url, options)
   return response
 }
 
+// Exchange Notion and Groq keys for a token the server accepts in place of its own keys.
+// The saved token is not sent, so one the server no longer accepts can be replaced.
+export const saveCredentials = async ({ notionApiKey, notionDatabaseId, groqApiKey }) => {
+  const headers = { 'Content-Type': 'application/json' }
+  const apiKey = await getStorageValue(STORAGEKEY.apiKey)
+  if (apiKey) headers['X-API-Key'] = apiKey
+
+  const response = await fetch((await getBaseURL()) + '/api/credentials', {
+    method: 'POST',
+    headers: headers,
+    body: JSON.stringify({ notionApiKey, notionDatabaseId, groqApiKey }),
+  })
+
+  if (!response.ok) throw new Error('Failed to save credentials')
+  const { token } = await response.json()
+  return token
+}
+
 // Save a new job application
 export const saveJob = async (job) => {
   const response = await fetchWrapper(``, {
@@ -5,9 +5,10 @@ import {
   getStreak,
   updateJob,
   compareJobPosting,
+  saveCredentials,
 } from './api.js'
 import { sendNotification } from '../utils/utils.js'
-import { SUCCESSMESSAGE, FAILUREMESSAGE, REQUESTACTION } from '../utils/constants.js'
+import { SUCCESSMESSAGE, FAILUREMESSAGE, REQUESTACTION, STORAGEKEY } from '../utils/constants.js'
 
 chrome.runtime.onMessage.addListener((request, _, sendResponse) => {
   if (request.action === REQUESTACTION.SAVEJOB) {
@@ -111,6 +112,25 @@ chrome.runtime.onMessage.addListener((request, _, sendResponse) => {
         })
       })
 
+    return true
+  } else if (request.action === REQUESTACTION.SAVECREDENTIALS) {
+    saveCredentials(request.credentials)
+      .then((token) => {
+        chrome.storage.local.set({ [STORAGEKEY.credentialsToken]: token }, () => {
+          sendResponse({
+            message: SUCCESSMESSAGE,
+            content: '',
+          })
+        })
+      })
+      .catch((error) => {
+        sendNotification(FAILUREMESSAGE, error.message)
+        sendResponse({
+          message: FAILUREMESSAGE,
+          error: error,
+        })
+      })
+
     return true
   }
 })
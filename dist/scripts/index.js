@@ -397,12 +397,37 @@ document.addEventListener('DOMContentLoaded', () => {
   // Method for saving api keys
   document.querySelector('.settings-save-button').addEventListener('click', () => {
     const baseURLInput = document.querySelector('#base-url-input').value
+    const apiKeyInput = document.querySelector('#api-key-input').value
+    const credentials = {
+      notionApiKey: document.querySelector('#notion-api-key-input').value,
+      notionDatabaseId: document.querySelector('#notion-database-id-input').value,
+      groqApiKey: document.querySelector('#groq-api-key-input').value,
+    }
     chrome.storage.local.set(
       {
         [STORAGEKEY.baseURL]: baseURLInput,
+        [STORAGEKEY.apiKey]: apiKeyInput,
+        [STORAGEKEY.notionApiKey]: credentials.notionApiKey,
+        [STORAGEKEY.notionDatabaseId]: credentials.notionDatabaseId,
+        [STORAGEKEY.groqApiKey]: credentials.groqApiKey,
       },
       () => {
-        sendNotification('Settings Saved', 'Keys Updated')
+        // Without keys of their own, requests use the server's
+        if (!credentials.notionApiKey && !credentials.notionDatabaseId && !credentials.groqApiKey) {
+          chrome.storage.local.remove(STORAGEKEY.credentialsToken, () => {
+            sendNotification('Settings Saved', 'Keys Updated')
+          })
+          return
+        }
+
+        chrome.runtime.sendMessage(
+          { action: REQUESTACTION.SAVECREDENTIALS, credentials: credentials },
+          (response) => {
+            if (response.message === SUCCESSMESSAGE) {
+              sendNotification('Settings Saved', 'Keys Updated')
+            }
+          }
+        )
       }
     )
   })
@@ -411,8 +436,35 @@ document.addEventListener('DOMContentLoaded', () => {
   document.querySelector('.settings-button').addEventListener('click', () => {
     document.querySelector('.settings-save-button').classList.remove('hidden')
 
-    chrome.storage.local.get([STORAGEKEY.baseURL], (result) => {
-      const fields = [{ id: 'base-url-input', label: 'Base URL', value: result.baseURL }]
+    const keys = [
+      STORAGEKEY.baseURL,
+      STORAGEKEY.apiKey,
+      STORAGEKEY.notionApiKey,
+      STORAGEKEY.notionDatabaseId,
+      STORAGEKEY.groqApiKey,
+    ]
+    chrome.storage.local.get(keys, (result) => {
+      const fields = [
+        { id: 'base-url-input', label: 'Base URL', value: result.baseURL },
+        { id: 'api-key-input', label: 'API Key', value: result.apiKey, type: 'password' },
+        {
+          id: 'notion-api-key-input',
+          label: 'Notion API Key',
+          value: result.notionApiKey,
+          type: 'password',
+        },
+        {
+          id: 'notion-database-id-input',
+          label: 'Notion Database ID',
+          value: result.notionDatabaseId,
+        },
+        {
+          id: 'groq-api-key-input',
+          label: 'Groq API Key',
+          value: result.groqApiKey,
+          type: 'password',
+        },
+      ]
 
       let content = ''
 
@@ -422,6 +474,7 @@ document.addEventListener('DOMContentLoaded', () => {
           <label for="${field.id}" class="block text-sm font-medium text-gray-700">${field.label}</label>
           <input
             id="${field.id}"
+            type="${field.type || 'text'}"
             class="mt-1 p-2 w-full border border-gray-300 rounded-lg"
             placeholder="Enter ${field.label}"
             value="${field.value || ''}"
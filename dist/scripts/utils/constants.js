@@ -8,6 +8,7 @@ export const REQUESTACTION = {
   GETSAVEDJOBS: 'GETSAVEDJOBS',
   SHOWDIALOG: 'SHOWDIALOG',
   COMPAREJOB: 'COMPAREJOB',
+  SAVECREDENTIALS: 'SAVECREDENTIALS',
 }
 
 export const RANGE = {
@@ -18,6 +19,11 @@ export const RANGE = {
 
 export const STORAGEKEY = {
   baseURL: 'baseURL',
+  apiKey: 'apiKey',
+  notionApiKey: 'notionApiKey',
+  notionDatabaseId: 'notionDatabaseId',
+  groqApiKey: 'groqApiKey',
+  credentialsToken: 'credentialsToken',
 }
 
 export const JOBSTATUS = {
@@ -6,16 +6,25 @@ jest.unstable_mockModule('../dist/scripts/utils/utils', () => ({
 
 const { getStorageValue } = await import('../dist/scripts/utils/utils')
 
-const { getStats, getStreak, getRecentlySavedJobs, updateJob, compareJobPosting, saveJob } =
-  await import('../dist/scripts/background/api')
+const {
+  getStats,
+  getStreak,
+  getRecentlySavedJobs,
+  updateJob,
+  compareJobPosting,
+  saveJob,
+  saveCredentials,
+} = await import('../dist/scripts/background/api')
 
 const mockJob = { title: 'Software Engineer', company: 'Google' }
 const mockResume = 'My resume content'
 const mockJobPosting = 'Job description content'
 
+const mockStorage = { baseURL: 'https:localhost:4000/api/job/' }
+
 beforeEach(() => {
   global.fetch = jest.fn()
-  getStorageValue.mockResolvedValue('https:localhost:4000/api/job/')
+  getStorageValue.mockImplementation(async (key) => mockStorage[key])
 })
 
 afterEach(() => {
@@ -108,6 +117,57 @@ describe('API Success cases', () => {
   })
 })
 
+describe('API authentication', () => {
+  afterEach(() => {
+    delete mockStorage.apiKey
+    delete mockStorage.credentialsToken
+  })
+
+  test('requests send the saved API key', async () => {
+    mockStorage.apiKey = 'jpk_1_secret'
+    fetch.mockResolvedValueOnce({ ok: true })
+
+    await updateJob('id', {})
+    expect(fetch).toHaveBeenCalledWith(expect.stringContaining('/id'), {
+      method: 'PUT',
+      headers: { 'Content-Type': 'application/json', 'X-API-Key': 'jpk_1_secret' },
+      body: JSON.stringify({}),
+    })
+  })
+
+  test('requests without a saved API key send no key', async () => {
+    fetch.mockResolvedValueOnce({ ok: true, json: async () => ({}) })
+
+    await getStreak()
+    expect(fetch).toHaveBeenCalledWith(expect.stringContaining('/streak'), {})
+  })
+
+  test('requests send the saved credentials token', async () => {
+    mockStorage.credentialsToken = 'token'
+    fetch.mockResolvedValueOnce({ ok: true, json: async () => ({}) })
+
+    await getStreak()
+    expect(fetch).toHaveBeenCalledWith(expect.stringContaining('/streak'), {
+      headers: { 'X-Credentials': 'token' },
+    })
+  })
+
+  test('saveCredentials exchanges the keys for a token', async () => {
+    mockStorage.apiKey = 'jpk_1_secret'
+    mockStorage.credentialsToken = 'stale'
+    fetch.mockResolvedValueOnce({ ok: true, json: async () => ({ token: 'token' }) })
+
+    const credentials = { notionApiKey: 'notion', notionDatabaseId: 'database', groqApiKey: 'groq' }
+    const token = await saveCredentials(credentials)
+    expect(fetch).toHaveBeenCalledWith(expect.stringContaining('/api/credentials'), {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json', 'X-API-Key': 'jpk_1_secret' },
+      body: JSON.stringify(credentials),
+    })
+    expect(token).toEqual('token')
+  })
+})
+
 describe('API failure cases', () => {
   test('saveJob throws error when fetch fails', async () => {
     fetch.mockResolvedValueOnce({ ok: false })
@@ -140,4 +200,9 @@ describe('API failure cases', () => {
     fetch.mockResolvedValueOnce({ ok: false })
     await expect(updateJob('id', {})).rejects.toThrow('Failed to update job')
   })
+
+  test('saveCredentials throws error when fetch fails', async () => {
+    fetch.mockResolvedValueOnce({ ok: false })
+    await expect(saveCredentials({})).rejects.toThrow('Failed to save credentials')
+  })
 })